@@ -1,7 +1,6 @@
 package main
 
 import (
-	"math/rand"
 	"strconv"
 
 	"github.com/EngoEngine/engo"
@@ -25,37 +24,60 @@ var RegularAttackAbility = Ability{
 	MPCost:      0,
 	TargetType:  TargetTypeSingleEnemy,
 	CastTimeFunc: func(You *Character) {
-		You.totalCastTime = 1.5 - (You.Dex / 100)
+		You.totalCastTime = 1.5 - (You.Stats().Dex / 100)
 		if You.totalCastTime < 0.2 {
 			You.totalCastTime = 0.2
 		}
 	},
 	EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
 		//Regular attack GO!
-
+		msgs := []string{}
+		for _, bad := range TargetBaddies {
+			msgs = append(msgs, "POW! "+You.Name+" throws a punch at the "+bad.Name+"!")
+			hit := PhysicalHit(CombatRand, You.Stats(), bad.Stats(), 5, 10)
+			if hit.Dodged {
+				msgs = append(msgs, "But it floats right out of the way!")
+				continue
+			}
+			if hit.Crit {
+				msgs = append(msgs, "Right in the ectoplasm! A critical hit!")
+				bad.AddStatus(DefDownStatus)
+				msgs = append(msgs, "The "+bad.Name+" looks a little see-through.")
+			}
+			msgs = append(msgs, "That's "+strconv.Itoa(hit.Damage)+" damage!")
+			ApplyDamage(&bad.StatsComponent, hit.Damage)
+		}
+		for _, msg := range msgs {
+			engo.Mailbox.Dispatch(You.chat(msg))
+		}
 	},
 }
 
 var DefendAbility = Ability{
-	Title:        "Defend yourself!",
-	Shorthand:    "Defend",
-	Description:  "Protect yourself this turn to take less damage!",
-	TargetType:   TargetTypeNone,
-	CastTimeFunc: func(You *Character) {},
-	EffectFunc:   func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {},
+	Title:       "Defend yourself!",
+	Shorthand:   "Defend",
+	Description: "Protect yourself this turn to take less damage!",
+	TargetType:  TargetTypeNone,
+	CastTimeFunc: func(You *Character) {
+		You.totalCastTime = 0.5
+	},
+	EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+		You.AddStatus(GuardStatus)
+		engo.Mailbox.Dispatch(You.chat(You.Name + " puts their guard up!"))
+	},
 }
 
 var LookAroundAbility = Ability{
 	Title:        "Look Around!",
 	Shorthand:    "Look",
-	Description:  "Look around the fight area for \nclues! Maybe something useful \nwill turn up!",
+	Description:  "Look around the fight area for clues! Maybe something useful will turn up!",
 	MPCost:       0,
 	TargetType:   TargetTypeNone,
 	CastTimeFunc: func(You *Character) {},
 	EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
 		//Roll perception
 		msgs := []string{"You look around the room..."}
-		if rng := rand.Intn(20) + 1; rng <= 5 {
+		if rng := CombatRand.Intn(20) + 1; rng <= 5 {
 			//Didn't see Anything
 			msgs = append(msgs, "But don't see anything of note.")
 		} else if rng <= 19 {
@@ -125,11 +147,7 @@ var LookAroundAbility = Ability{
 			}
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -160,11 +178,7 @@ var SafeSearchAbility = Ability{
 			You.AddAbility(AskPinAbility)
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -207,11 +221,7 @@ var AskPinAbility = Ability{
 			}
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -219,7 +229,7 @@ var AskPinAbility = Ability{
 var GuessPinAbility = Ability{
 	Title:        "Guess the Pin!",
 	Shorthand:    "Guess",
-	Description:  "Try a random pin on the safe!",
+	Description:  "Try a pin on the safe! Who knows, you might get lucky!",
 	MPCost:       0,
 	TargetType:   TargetTypeNone,
 	CastTimeFunc: func(You *Character) {},
@@ -228,39 +238,11 @@ var GuessPinAbility = Ability{
 			"It's FOUR DIGITS!",
 			"That's literally a one in ten thousand chance!",
 			"But, despite the odds, you try to guess anyway...",
-			"You punch in 4 random numbers and hit enter.",
-		}
-		if guess := rand.Intn(10000); guess == 1234 {
-			cash, _ := common.LoadedPlayer("fight/cash.wav")
-			if cash.IsPlaying() {
-				cash.Pause()
-				cash.Rewind()
-			}
-			cash.Play()
-			msgs = append(msgs,
-				"Wow. You actually guessed it!",
-				"Great job!",
-				"You opened the safe!",
-			)
-			You.RemoveAbility("Guess the Pin!")
-			You.RemoveAbility("Ask about the Pin!")
-			You.RemoveAbility("Input the Pin!")
-			You.RemoveAbility("Distract and Dodge")
-			You.AddAbility(GrabItemInSafeAbility)
-		} else {
-			//wrong sound
-			msgs = append(msgs,
-				"Guess that wasn't it.",
-				"You can always try again!",
-			)
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
+		enterSafePin(You)
 	},
 }
 
@@ -272,22 +254,59 @@ var InputPinAbility = Ability{
 	TargetType:   TargetTypeNone,
 	CastTimeFunc: func(You *Character) {},
 	EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
-		msgs := []string{
-			"You carefully input the pin.",
-		}
-		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+		engo.Mailbox.Dispatch(You.chat("You carefully input the pin."))
+		enterSafePin(You)
+	},
+}
+
+// safePin is the president's safe's pin. He never changed it from the
+// factory default.
+const safePin = "1234"
+
+// enterSafePin opens the keypad over the fight for You to try a pin on the
+// safe. Wrong pins are counted in the save.
+func enterSafePin(You *Character) {
+	engo.Mailbox.Dispatch(KeypadSetMessage{
+		Length: len(safePin),
+		Check: func(code string) bool {
+			return code == safePin
+		},
+		Done: func(code string, ok bool) {
+			if !ok {
+				CurrentSave.SafePinMisses++
+				msgs := []string{
+					"BZZT! " + code + " wasn't it.",
+					"You can always try again!",
+				}
+				if CurrentSave.SafePinMisses >= 3 && !CurrentSave.RecruitedMe {
+					msgs = append(msgs, "Maybe someone who works here knows the pin?")
+				}
+				for _, msg := range msgs {
+					engo.Mailbox.Dispatch(You.chat(msg))
+				}
+				return
+			}
+			cash, _ := common.LoadedPlayer("fight/cash.wav")
+			if cash.IsPlaying() {
+				cash.Pause()
+				cash.Rewind()
+			}
+			cash.Play()
+			msgs := []string{
+				"Click!",
+				"You opened the safe!",
+			}
 			You.RemoveAbility("Guess the Pin!")
 			You.RemoveAbility("Ask about the Pin!")
 			You.RemoveAbility("Input the Pin!")
 			You.RemoveAbility("Distract and Dodge")
 			You.AddAbility(GrabItemInSafeAbility)
-		}
-	},
+			for _, msg := range msgs {
+				engo.Mailbox.Dispatch(You.chat(msg))
+			}
+		},
+	})
+	engo.Mailbox.Dispatch(PhaseInterruptMessage{Phase: KeypadPhase})
 }
 
 var DistractAndDodgeAbility = Ability{
@@ -313,7 +332,7 @@ var DistractAndDodgeAbility = Ability{
 		}
 		//Ghost Blast Animation!
 		//Dodge! Quickly!
-		if rand.Intn(100)+1+20+int(You.Dex) > int(TargetBaddies[0].Dex)+rand.Intn(100)+1 {
+		if Contest(CombatRand, You.Stats().Dex+20, TargetBaddies[0].Stats().Dex) {
 			msgs = append(msgs,
 				"You narrowly dodge the blast",
 				"It hits the safe dead-on!",
@@ -326,7 +345,7 @@ var DistractAndDodgeAbility = Ability{
 			You.RemoveAbility("Distract and Dodge")
 			You.Abilities = append(You.Abilities, GrabItemInSafeAbility)
 		} else {
-			dmg := rand.Intn(30) + 15 + int(TargetBaddies[0].Str+TargetBaddies[0].Int)
+			dmg := MagicalDamage(CombatRand, TargetBaddies[0].Stats(), You.Stats(), 15+TargetBaddies[0].Stats().Str, 30)
 			msgs = append(msgs,
 				"The ghost fires the blast right into your FACE!",
 				"Ooof. That's gotta hurt",
@@ -337,14 +356,10 @@ var DistractAndDodgeAbility = Ability{
 			// 	snd.Play()
 			// }
 			//engo.Mailbox.Dispatch(ScreenShakeMessage{})
-			You.HP -= float32(dmg)
+			ApplyDamage(&You.StatsComponent, dmg)
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -373,11 +388,7 @@ var GrabItemInSafeAbility = Ability{
 		}
 		You.RemoveAbility("Grab whatever's in that safe!")
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -398,11 +409,7 @@ var GlintSearchAbility = Ability{
 		//Add baddie "Wall" to be knocked down
 		You.RemoveAbility("Look closer at the wall!")
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
@@ -417,20 +424,24 @@ var MedKitSearchAbility = Ability{
 		msgs := []string{
 			"You approach the medkit",
 		}
-		if rand.Intn(100)+1+int(You.Int) > 35 {
+		if Check(CombatRand, You.Stats().Int, 35) {
 			msgs = append(msgs, "And open it!", "Inside you find")
-			bandaidcount := rand.Intn(6) - 2
-			watercount := rand.Intn(6) - 3
+			bandaidcount := CombatRand.Intn(6) - 2
+			watercount := CombatRand.Intn(6) - 3
 			if bandaidcount > 0 {
 				msgs = append(msgs, strconv.Itoa(bandaidcount)+" bandages")
-				CurrentSave.BandageCount += bandaidcount
+				if You.AddItem(Item{ID: BandageItemID, Quantity: bandaidcount}) < bandaidcount {
+					msgs = append(msgs, "but you can't carry all of them")
+				}
 				if watercount > 0 {
 					msgs = append(msgs, "and")
 				}
 			}
 			if watercount > 0 {
 				msgs = append(msgs, strconv.Itoa(watercount)+" sports drinks")
-				CurrentSave.DrinkCount += watercount
+				if You.AddItem(Item{ID: SportsDrinkItemID, Quantity: watercount}) < watercount {
+					msgs = append(msgs, "but you can't carry all of them")
+				}
 			} else if bandaidcount <= 0 {
 				msgs = append(msgs, "nothing.")
 			}
@@ -439,7 +450,7 @@ var MedKitSearchAbility = Ability{
 				"There's plenty more stuff inside!",
 			)
 		} else {
-			dmg := rand.Intn(20) + 5
+			dmg := PhysicalDamage(CombatRand, StatsComponent{}, You.Stats(), 5, 20)
 			msgs = append(msgs,
 				"You reach into the bag",
 				"But that wasn't a zipper! They're teeth!",
@@ -447,15 +458,11 @@ var MedKitSearchAbility = Ability{
 				"And it takes a chomp at your arm!",
 				"Ouchie! That looks like "+strconv.Itoa(dmg)+" points of damage!",
 			)
-			You.HP -= float32(dmg)
+			ApplyDamage(&You.StatsComponent, dmg)
 			// A mimic appears!
 		}
 		for _, msg := range msgs {
-			engo.Mailbox.Dispatch(CombatLogMessage{
-				Msg:  msg,
-				Fnt:  You.Font,
-				Clip: You.Clip,
-			})
+			engo.Mailbox.Dispatch(You.chat(msg))
 		}
 	},
 }
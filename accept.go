@@ -1,18 +1,67 @@
 package main
 
 import (
+	"image/color"
+
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 )
 
-var YesSelectedMessageType = "Yes Selected Message"
+// Choice is one option in the choice box. Func is called when it's picked.
+// Disabled choices are greyed out and can't be picked. Default is where the
+// cursor starts, and Cancel is picked when B is pressed.
+type Choice struct {
+	Label    string
+	Func     func()
+	Disabled bool
+	Default  bool
+	Cancel   bool
+}
+
+// yesNo is the yes/no box, with yes calling accept.
+func yesNo(accept func()) []Choice {
+	return []Choice{
+		{Label: "yes", Func: accept, Default: true},
+		{Label: "no", Cancel: true},
+	}
+}
+
+// defaultChoice is the choice the cursor starts on: the one marked Default,
+// or else the first that isn't disabled.
+func defaultChoice(choices []Choice) int {
+	for i, c := range choices {
+		if c.Default {
+			return i
+		}
+	}
+	for i, c := range choices {
+		if !c.Disabled {
+			return i
+		}
+	}
+	return 0
+}
+
+// cancelChoice is the choice B picks, or -1 if there isn't one.
+func cancelChoice(choices []Choice) int {
+	for i, c := range choices {
+		if c.Cancel {
+			return i
+		}
+	}
+	return -1
+}
 
-type YesSelectedMessage struct {
-	Selected bool
+var ChoiceSelectedMessageType = "Choice Selected Message"
+
+// ChoiceSelectedMessage asks the AcceptSystem which choice the cursor is on.
+// Index is left at -1 if it isn't on any.
+type ChoiceSelectedMessage struct {
+	Index int
 }
 
-func (*YesSelectedMessage) Type() string { return YesSelectedMessageType }
+func (*ChoiceSelectedMessage) Type() string { return ChoiceSelectedMessageType }
 
 var AcceptSystemPauseMessageType = "Accept System Pause Message"
 
@@ -22,17 +71,32 @@ type AcceptSystemPauseMessage struct {
 
 func (AcceptSystemPauseMessage) Type() string { return AcceptSystemPauseMessageType }
 
+// Where the choices go in the box. Rows are acceptRowHeight apart, and the
+// columns share the box's width between its margins.
+const (
+	acceptTop       = 80
+	acceptMargin    = 25
+	acceptRowHeight = 30
+)
+
 type AcceptSystem struct {
 	Fnt           *common.Font
 	BackgroundURL string
 
+	world  *ecs.World
 	cursor *CursorSystem
 
-	bg      sprite
-	yes, no selection
+	bg       sprite
+	bgHeight float32
+
+	choices []Choice
+	columns int
+	options []*selection
+	shown   int
 }
 
 func (s *AcceptSystem) New(w *ecs.World) {
+	s.world = w
 	for _, system := range w.Systems() {
 		switch sys := system.(type) {
 		case *CursorSystem:
@@ -47,29 +111,16 @@ func (s *AcceptSystem) New(w *ecs.World) {
 	s.bg.Position = engo.Point{X: 200, Y: 70}
 	s.bg.Scale = engo.Point{X: 0.4, Y: 0.5}
 	w.AddEntity(&s.bg)
+	s.bgHeight = s.bg.Scale.Y
 
-	s.yes = selection{BasicEntity: ecs.NewBasic()}
-	s.yes.Drawable = common.Text{
-		Text: "yes",
-		Font: s.Fnt,
-	}
-	s.yes.SetShader(common.HUDShader)
-	s.yes.SetZIndex(10005)
-	s.yes.Position = engo.Point{X: 225, Y: 80}
-	s.yes.Scale = engo.Point{X: 0.35, Y: 0.35}
-	w.AddEntity(&s.yes)
-
-	s.no = selection{BasicEntity: ecs.NewBasic()}
-	s.no.Drawable = common.Text{
-		Text: "no",
-		Font: s.Fnt,
-	}
-	s.no.SetShader(common.HUDShader)
-	s.no.SetZIndex(10005)
-	s.no.Position = engo.Point{X: 360, Y: 80}
-	s.no.Scale = engo.Point{X: 0.35, Y: 0.35}
-	w.AddEntity(&s.no)
-
+	engo.Mailbox.Listen(AcceptSetMessageType, func(message engo.Message) {
+		msg, ok := message.(AcceptSetMessage)
+		if !ok {
+			return
+		}
+		s.choices = msg.choices()
+		s.columns = msg.Columns
+	})
 	engo.Mailbox.Listen(AcceptSystemPauseMessageType, func(message engo.Message) {
 		msg, ok := message.(AcceptSystemPauseMessage)
 		if !ok {
@@ -81,12 +132,25 @@ func (s *AcceptSystem) New(w *ecs.World) {
 			s.unpause()
 		}
 	})
-	engo.Mailbox.Listen(YesSelectedMessageType, func(message engo.Message) {
-		msg, ok := message.(*YesSelectedMessage)
+	engo.Mailbox.Listen(ChoiceSelectedMessageType, func(message engo.Message) {
+		msg, ok := message.(*ChoiceSelectedMessage)
+		if !ok {
+			return
+		}
+		for i, o := range s.options[:s.shown] {
+			if o.Selected {
+				msg.Index = i
+			}
+		}
+	})
+	engo.Mailbox.Listen(HUDClickMessageType, func(message engo.Message) {
+		msg, ok := message.(*HUDClickMessage)
 		if !ok {
 			return
 		}
-		msg.Selected = s.yes.Selected
+		if s.bg.shows(msg.Pt) {
+			msg.Hit = true
+		}
 	})
 }
 
@@ -96,17 +160,73 @@ func (s *AcceptSystem) Update(dt float32) {}
 
 func (s *AcceptSystem) pause() {
 	s.bg.Hidden = true
-	s.yes.Hidden = true
-	s.cursor.Remove(s.yes.BasicEntity)
-	s.no.Hidden = true
-	s.cursor.Remove(s.no.BasicEntity)
+	for _, o := range s.options[:s.shown] {
+		o.Hidden = true
+		s.cursor.Remove(o.BasicEntity)
+	}
+	if s.shown > 0 {
+		engo.Mailbox.Dispatch(CursorJumpSetMessage{Jump: 1})
+	}
+	s.shown = 0
 }
 
 func (s *AcceptSystem) unpause() {
 	s.bg.Hidden = false
-	s.yes.Hidden = false
-	s.yes.Selected = true
-	s.cursor.AddByInterface(&s.yes)
-	s.no.Hidden = false
-	s.cursor.AddByInterface(&s.no)
+	for len(s.options) < len(s.choices) {
+		o := &selection{BasicEntity: ecs.NewBasic()}
+		o.SetShader(common.HUDShader)
+		o.SetZIndex(10005)
+		o.Scale = engo.Point{X: 0.35, Y: 0.35}
+		o.Hidden = true
+		s.world.AddEntity(o)
+		s.options = append(s.options, o)
+	}
+
+	cols := s.columns
+	if cols <= 0 || cols > len(s.choices) {
+		cols = len(s.choices)
+	}
+	s.layout(cols)
+	def := defaultChoice(s.choices)
+	for i, c := range s.choices {
+		o := s.options[i]
+		o.Drawable = common.Text{
+			Text: c.Label,
+			Font: s.Fnt,
+		}
+		o.Color = color.White
+		if c.Disabled {
+			o.Color = menuDisabled
+		}
+		o.Hidden = false
+		o.Selected = i == def
+		s.cursor.AddByInterface(o)
+	}
+	s.shown = len(s.choices)
+	if s.shown > 0 {
+		engo.Mailbox.Dispatch(CursorJumpSetMessage{Jump: cols})
+	}
+}
+
+// layout puts the choices in a grid cols wide, filling rows left to right,
+// and stretches the box down to fit them.
+func (s *AcceptSystem) layout(cols int) {
+	if cols == 0 {
+		return
+	}
+	rows := (len(s.choices) + cols - 1) / cols
+	width := s.bg.Drawable.Width() * s.bg.Scale.X
+	colWidth := (width - 2*acceptMargin) / float32(cols)
+	for i := range s.choices {
+		s.options[i].Position = engo.Point{
+			X: s.bg.Position.X + acceptMargin + float32(i%cols)*colWidth,
+			Y: acceptTop + float32(i/cols)*acceptRowHeight,
+		}
+	}
+
+	s.bg.Scale.Y = s.bgHeight
+	need := acceptTop - s.bg.Position.Y + float32(rows)*acceptRowHeight
+	if h := s.bg.Drawable.Height(); h > 0 && need/h > s.bg.Scale.Y {
+		s.bg.Scale.Y = need / h
+	}
 }
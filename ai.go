@@ -1,12 +1,273 @@
 package main
 
+import (
+	"math/rand"
+	"time"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+)
+
+var AISystemPauseMessageType = "AI System Pause Message"
+
+type AISystemPauseMessage struct {
+	Pause bool
+}
+
+func (AISystemPauseMessage) Type() string { return AISystemPauseMessageType }
+
 type AIComponent struct {
 	Attacks          []Attack
 	MinWait, MaxWait float32
+	Targeting        TargetStrategy
+	SelectedAttack   Attack
+	Threat           map[uint64]float32
+
+	wait    float32
+	waiting bool
+}
+
+// AddThreat makes the baddie more likely to go after chara when using
+// TargetHighestThreat.
+func (c *AIComponent) AddThreat(chara *Character, amount float32) {
+	if c.Threat == nil {
+		c.Threat = make(map[uint64]float32)
+	}
+	c.Threat[chara.ID()] += amount
 }
 
 type Attack struct {
+	Name       string
 	EffectFunc func(bad *Baddie, TargetPlayers []*Character, TargetBaddies []*Baddie)
 	AttackTime float32
 	MPCost     float32
+	Weight     float32
+	TargetType Target
+}
+
+// TargetStrategy picks which of the candidates a baddie goes after.
+type TargetStrategy func(rng *rand.Rand, bad *Baddie, candidates []*Character) *Character
+
+func TargetRandom(rng *rand.Rand, bad *Baddie, candidates []*Character) *Character {
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rng.Intn(len(candidates))]
+}
+
+func TargetLowestHP(rng *rand.Rand, bad *Baddie, candidates []*Character) *Character {
+	var ret *Character
+	for _, c := range candidates {
+		if ret == nil || c.HP < ret.HP {
+			ret = c
+		}
+	}
+	return ret
+}
+
+func TargetHighestThreat(rng *rand.Rand, bad *Baddie, candidates []*Character) *Character {
+	var ret *Character
+	for _, c := range candidates {
+		if ret == nil || bad.Threat[c.ID()] > bad.Threat[ret.ID()] {
+			ret = c
+		}
+	}
+	if ret == nil || bad.Threat[ret.ID()] == 0 {
+		return TargetRandom(rng, bad, candidates)
+	}
+	return ret
+}
+
+// ChooseAttack picks one of the attacks weighted by Weight (0 counts as 1)
+// that can be paid for with mp. It returns -1 if none can.
+func ChooseAttack(rng *rand.Rand, attacks []Attack, mp float32) int {
+	var total float32
+	for _, a := range attacks {
+		if a.MPCost <= mp {
+			total += attackWeight(a)
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	roll := rng.Float32() * total
+	last := -1
+	for i, a := range attacks {
+		if a.MPCost > mp {
+			continue
+		}
+		last = i
+		roll -= attackWeight(a)
+		if roll < 0 {
+			return i
+		}
+	}
+	return last
+}
+
+func attackWeight(a Attack) float32 {
+	if a.Weight <= 0 {
+		return 1
+	}
+	return a.Weight
+}
+
+type aiEntity struct {
+	chara  *Character
+	baddie *Baddie
+}
+
+// AISystem decides when baddies attack, what with and who at. Paying for and
+// casting the attack is left up to the BarSystem, same as for characters.
+type AISystem struct {
+	Rand *rand.Rand
+
+	entities              []aiEntity
+	paused, skipNextFrame bool
+}
+
+func (s *AISystem) New(w *ecs.World) {
+	if s.Rand == nil {
+		s.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	engo.Mailbox.Listen(AISystemPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(AISystemPauseMessage)
+		if !ok {
+			return
+		}
+		if msg.Pause {
+			s.pause()
+		} else {
+			s.unpause()
+		}
+	})
+}
+
+func (s *AISystem) Add(chara *Character, bad *Baddie) {
+	s.entities = append(s.entities, aiEntity{chara, bad})
+}
+
+func (s *AISystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(Characterable)
+	if ok {
+		s.Add(o.GetCharacter(), nil)
+	}
+	o2, ok := i.(Baddieable)
+	if ok {
+		s.Add(nil, o2.GetBaddie())
+	}
+}
+
+func (s *AISystem) Remove(b ecs.BasicEntity) {
+	d := -1
+	for i, e := range s.entities {
+		if e.chara != nil {
+			if e.chara.ID() == b.ID() {
+				d = i
+				break
+			}
+		} else if e.baddie != nil {
+			if e.baddie.ID() == b.ID() {
+				d = i
+				break
+			}
+		}
+	}
+	if d >= 0 {
+		s.entities = append(s.entities[:d], s.entities[d+1:]...)
+	}
+}
+
+func (s *AISystem) Update(dt float32) {
+	if s.skipNextFrame {
+		s.skipNextFrame = false
+		return
+	}
+	if s.paused {
+		return
+	}
+	for _, e := range s.entities {
+		if e.baddie == nil {
+			continue
+		}
+		s.think(e.baddie, dt)
+	}
+}
+
+func (s *AISystem) think(bad *Baddie, dt float32) {
+	if bad.isCasting || bad.HP <= 0 || bad.Stunned() {
+		return
+	}
+	if !bad.waiting {
+		bad.wait = bad.MinWait
+		if bad.MaxWait > bad.MinWait {
+			bad.wait += s.Rand.Float32() * (bad.MaxWait - bad.MinWait)
+		}
+		bad.waiting = true
+	}
+	bad.wait -= dt
+	if bad.wait > 0 {
+		return
+	}
+	bad.waiting = false
+	idx := ChooseAttack(s.Rand, bad.Attacks, bad.MP)
+	if idx < 0 {
+		return
+	}
+	atk := bad.Attacks[idx]
+	bad.SelectedAttack = atk
+	bad.TargetPlayers, bad.TargetBaddies = s.targets(bad, atk.TargetType)
+	engo.Mailbox.Dispatch(CastStartMessage{Baddie: bad})
+}
+
+// targets resolves an attack's target type from the baddie's point of view,
+// so enemies are the characters and friends are the other baddies.
+func (s *AISystem) targets(bad *Baddie, t Target) ([]*Character, []*Baddie) {
+	players := make([]*Character, 0)
+	baddies := make([]*Baddie, 0)
+	for _, e := range s.entities {
+		if e.chara != nil && e.chara.HP > 0 {
+			players = append(players, e.chara)
+		}
+		if e.baddie != nil && e.baddie.HP > 0 {
+			baddies = append(baddies, e.baddie)
+		}
+	}
+	pick := bad.Targeting
+	if pick == nil {
+		pick = TargetRandom
+	}
+	switch t {
+	case TargetTypeSingleEnemy:
+		if c := pick(s.Rand, bad, players); c != nil {
+			return []*Character{c}, []*Baddie{}
+		}
+	case TargetTypeAllEnemy:
+		return players, []*Baddie{}
+	case TargetTypeSingleFriend:
+		if len(baddies) > 0 {
+			return []*Character{}, []*Baddie{baddies[s.Rand.Intn(len(baddies))]}
+		}
+	case TargetTypeAllFriend:
+		return []*Character{}, baddies
+	case TargetTypeSingleAny:
+		if n := len(players) + len(baddies); n > 0 {
+			if i := s.Rand.Intn(n); i >= len(players) {
+				return []*Character{}, []*Baddie{baddies[i-len(players)]}
+			}
+			return []*Character{pick(s.Rand, bad, players)}, []*Baddie{}
+		}
+	case TargetTypeAll:
+		return players, baddies
+	}
+	return []*Character{}, []*Baddie{}
+}
+
+func (s *AISystem) pause() {
+	s.paused = true
+}
+
+func (s *AISystem) unpause() {
+	s.paused = false
+	s.skipNextFrame = true
 }
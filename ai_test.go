@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+)
+
+func newTestCharacter(hp float32) *Character {
+	c := &Character{BasicEntity: ecs.NewBasic()}
+	c.HP = hp
+	return c
+}
+
+func TestChooseAttackWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	attacks := []Attack{
+		{Name: "jab", Weight: 3},
+		{Name: "kick"}, // 0 counts as 1
+		{Name: "never", Weight: -1, MPCost: 100},
+	}
+	counts := make([]int, len(attacks))
+	const rolls = 8000
+	for i := 0; i < rolls; i++ {
+		counts[ChooseAttack(rng, attacks, 0)]++
+	}
+	if counts[2] != 0 {
+		t.Errorf("picked an attack it couldn't pay for %v times", counts[2])
+	}
+	// jab should come up about 3 times out of 4.
+	if got := float64(counts[0]) / rolls; got < 0.72 || got > 0.78 {
+		t.Errorf("jab was picked %.2f of the time, want about 0.75", got)
+	}
+}
+
+func TestChooseAttackMP(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	attacks := []Attack{
+		{Name: "fireball", MPCost: 10},
+		{Name: "punch", MPCost: 0},
+		{Name: "meteor", MPCost: 50},
+	}
+	for _, tt := range []struct {
+		mp   float32
+		want map[int]bool
+	}{
+		{mp: 0, want: map[int]bool{1: true}},
+		{mp: 10, want: map[int]bool{0: true, 1: true}},
+		{mp: 50, want: map[int]bool{0: true, 1: true, 2: true}},
+	} {
+		seen := make(map[int]bool)
+		for i := 0; i < 200; i++ {
+			seen[ChooseAttack(rng, attacks, tt.mp)] = true
+		}
+		if len(seen) != len(tt.want) {
+			t.Errorf("with %v mp picked %v, want %v", tt.mp, seen, tt.want)
+		}
+		for i := range seen {
+			if !tt.want[i] {
+				t.Errorf("with %v mp picked %v", tt.mp, attacks[i].Name)
+			}
+		}
+	}
+
+	if got := ChooseAttack(rng, []Attack{{MPCost: 5}}, 4); got != -1 {
+		t.Errorf("got attack %v with too little mp, want -1", got)
+	}
+	if got := ChooseAttack(rng, nil, 100); got != -1 {
+		t.Errorf("got attack %v with no attacks, want -1", got)
+	}
+}
+
+func TestTargetStrategies(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a, b, c := newTestCharacter(30), newTestCharacter(10), newTestCharacter(20)
+	party := []*Character{a, b, c}
+	bad := &Baddie{}
+
+	if got := TargetLowestHP(rng, bad, party); got != b {
+		t.Errorf("lowest hp picked %v hp, want 10", got.HP)
+	}
+
+	// Without any threat it falls back to random, which should hit everyone.
+	seen := make(map[*Character]bool)
+	for i := 0; i < 100; i++ {
+		seen[TargetHighestThreat(rng, bad, party)] = true
+	}
+	if len(seen) != len(party) {
+		t.Errorf("with no threat only %v of %v were targeted", len(seen), len(party))
+	}
+
+	bad.AddThreat(a, 5)
+	bad.AddThreat(c, 8)
+	bad.AddThreat(a, 4)
+	for i := 0; i < 10; i++ {
+		if got := TargetHighestThreat(rng, bad, party); got != a {
+			t.Fatalf("highest threat picked the one with %v threat, want 9", bad.Threat[got.ID()])
+		}
+	}
+
+	seen = make(map[*Character]bool)
+	for i := 0; i < 100; i++ {
+		seen[TargetRandom(rng, bad, party)] = true
+	}
+	if len(seen) != len(party) {
+		t.Errorf("random only targeted %v of %v", len(seen), len(party))
+	}
+
+	for name, strategy := range map[string]TargetStrategy{
+		"random":         TargetRandom,
+		"lowest hp":      TargetLowestHP,
+		"highest threat": TargetHighestThreat,
+	} {
+		if got := strategy(rng, bad, nil); got != nil {
+			t.Errorf("%v picked a target with no candidates", name)
+		}
+	}
+}
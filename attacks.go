@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/EngoEngine/engo"
+)
+
+var GhostEnergyBlastAttack = Attack{
+	Name:       "Energy Blast",
+	AttackTime: 3,
+	MPCost:     20,
+	Weight:     1,
+	TargetType: TargetTypeSingleEnemy,
+	EffectFunc: func(bad *Baddie, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+		msgs := []string{
+			"The " + bad.Name + " fires an ENERGY BLAST!",
+		}
+		for _, tar := range TargetPlayers {
+			hit := MagicalHit(CombatRand, bad.Stats(), tar.Stats(), 10, 20)
+			if hit.Dodged {
+				msgs = append(msgs, tar.Name+" ducks under the blast!")
+				continue
+			}
+			if hit.Crit {
+				msgs = append(msgs, "It's a direct hit!")
+			}
+			msgs = append(msgs, "It hits "+tar.Name+" for "+strconv.Itoa(hit.Damage)+" damage!")
+			ApplyDamage(&tar.StatsComponent, hit.Damage)
+			if hit.Crit {
+				tar.AddStatus(StunStatus)
+				msgs = append(msgs, tar.Name+" is seeing stars!")
+			}
+		}
+		for _, msg := range msgs {
+			engo.Mailbox.Dispatch(bad.chat(msg))
+		}
+	},
+}
+
+var GhostBiteAttack = Attack{
+	Name:       "Bloody Bite",
+	AttackTime: 1.5,
+	Weight:     3,
+	TargetType: TargetTypeSingleEnemy,
+	EffectFunc: func(bad *Baddie, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+		msgs := []string{}
+		for _, tar := range TargetPlayers {
+			msgs = append(msgs, "The "+bad.Name+" lunges at "+tar.Name+"!")
+			hit := PhysicalHit(CombatRand, bad.Stats(), tar.Stats(), 5, 10)
+			if hit.Dodged {
+				msgs = append(msgs, "But its teeth only catch air!")
+				continue
+			}
+			if hit.Crit {
+				msgs = append(msgs, "It chomps down HARD!")
+			}
+			msgs = append(msgs, "That's "+strconv.Itoa(hit.Damage)+" damage!")
+			ApplyDamage(&tar.StatsComponent, hit.Damage)
+			if hit.Crit && !tar.HasStatus(PoisonStatus.Name) {
+				tar.AddStatus(PoisonStatus)
+				msgs = append(msgs, "The bite is oozing something green. "+tar.Name+" is poisoned!")
+			}
+		}
+		for _, msg := range msgs {
+			engo.Mailbox.Dispatch(bad.chat(msg))
+		}
+	},
+}
+
+var GhostWailAttack = Attack{
+	Name:       "Spooky Wail",
+	AttackTime: 2.5,
+	MPCost:     10,
+	Weight:     1,
+	TargetType: TargetTypeAllEnemy,
+	EffectFunc: func(bad *Baddie, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+		msgs := []string{
+			"The " + bad.Name + " lets out a bone-chilling WAIL!",
+		}
+		for _, tar := range TargetPlayers {
+			dmg := MagicalDamage(CombatRand, StatsComponent{}, tar.Stats(), 3, 6)
+			msgs = append(msgs, tar.Name+" takes "+strconv.Itoa(dmg)+" damage!")
+			ApplyDamage(&tar.StatsComponent, dmg)
+			tar.AddStatus(StrDownStatus)
+		}
+		if len(TargetPlayers) > 0 {
+			msgs = append(msgs, "Everyone's knees are knocking too hard to hit back properly!")
+		}
+		for _, msg := range msgs {
+			engo.Mailbox.Dispatch(bad.chat(msg))
+		}
+	},
+}
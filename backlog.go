@@ -0,0 +1,137 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// BacklogMenuMessage opens the backlog. The PhaseSystem sends it on the
+// BacklogPhase.
+type BacklogMenuMessage struct{}
+
+var BacklogMenuMessageType = "Backlog Menu Message"
+
+func (BacklogMenuMessage) Type() string { return BacklogMenuMessageType }
+
+// backlogLabelLength is how much of a message fits in the list. The whole
+// thing shows under it while it's highlighted.
+const backlogLabelLength = 48
+
+// BacklogSystem shows the MessageHistory so messages that went by too fast
+// can be read again. The Log button opens it in any phase, and closing it
+// goes back to whatever was happening.
+type BacklogSystem struct {
+	Fnt           *common.Font
+	BackgroundURL string
+
+	bg   sprite
+	menu ListMenu
+
+	open, skipNextFrame bool
+}
+
+func (s *BacklogSystem) New(w *ecs.World) {
+	s.bg = sprite{BasicEntity: ecs.NewBasic()}
+	s.bg.Drawable, _ = common.LoadedSprite(s.BackgroundURL)
+	s.bg.SetShader(common.HUDShader)
+	s.bg.SetZIndex(10004)
+	s.bg.Scale = engo.Point{
+		X: engo.GameWidth() / s.bg.Drawable.Width(),
+		Y: engo.GameHeight() / s.bg.Drawable.Height(),
+	}
+	s.bg.Hidden = true
+	w.AddEntity(&s.bg)
+
+	s.menu = ListMenu{
+		Position:         engo.Point{X: 60, Y: 30},
+		RowHeight:        26,
+		Rows:             9,
+		DetailPosition:   engo.Point{X: 60, Y: 280},
+		DescriptionWidth: 520,
+		Font:             s.Fnt,
+		Scale:            engo.Point{X: 0.35, Y: 0.35},
+		HUD:              true,
+		OnCancel: func() {
+			s.close()
+		},
+	}
+	s.menu.Setup(w)
+
+	engo.Mailbox.Listen(BacklogMenuMessageType, func(message engo.Message) {
+		_, ok := message.(BacklogMenuMessage)
+		if !ok {
+			return
+		}
+		s.show()
+	})
+}
+
+func (s *BacklogSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *BacklogSystem) Update(dt float32) {
+	if s.skipNextFrame {
+		s.skipNextFrame = false
+		return
+	}
+	if !s.open {
+		if button("Log").JustPressed() {
+			engo.Mailbox.Dispatch(PhaseInterruptMessage{Phase: BacklogPhase})
+		}
+		return
+	}
+	if button("Log").JustPressed() {
+		s.close()
+		return
+	}
+	s.menu.Update()
+}
+
+// entries are the history, oldest first.
+func (s *BacklogSystem) entries() []MenuEntry {
+	var entries []MenuEntry
+	for i := 0; i < MessageHistory.Len(); i++ {
+		e := MessageHistory.At(i)
+		label := e.Msg
+		title := e.Time.Format("15:04")
+		if e.Speaker != "" {
+			label = e.Speaker + ": " + label
+			title = e.Speaker + ", " + title
+		}
+		entries = append(entries, MenuEntry{
+			Label:       shorten(label, backlogLabelLength),
+			Title:       title,
+			Description: e.Msg,
+			Enabled:     true,
+		})
+	}
+	return entries
+}
+
+// shorten cuts s down to n letters, ending it with "..." if anything was cut.
+func shorten(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-3]) + "..."
+}
+
+func (s *BacklogSystem) show() {
+	s.open = true
+	s.skipNextFrame = true
+	s.bg.Hidden = false
+	entries := s.entries()
+	s.menu.SetEntries(entries, s.Fnt, s.menu.Scale)
+	s.menu.Select(len(entries) - 1)
+	s.menu.Show()
+}
+
+func (s *BacklogSystem) close() {
+	s.open = false
+	s.bg.Hidden = true
+	s.menu.Hide()
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestShorten(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a bit too long", 10, "a bit t..."},
+		{"Len: ☃☃☃☃☃☃☃☃", 10, "Len: ☃☃..."},
+		{"ééééééééééé", 10, "ééééééé..."},
+	} {
+		if got := shorten(tt.s, tt.n); got != tt.want {
+			t.Errorf("shorten(%q, %v) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"image/color"
+
 	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 )
 
@@ -13,20 +16,30 @@ type BaddieState struct {
 type BaddieInfo struct {
 	Name         string
 	Spritesheet  string
+	CellWidth    int
+	CellHeight   int
+	Scale        engo.Point
 	HP, MP       float32
 	MaxHP, MaxMP float32
 	Str, Def     float32
 	Dex, Int     float32
 	Font         *common.Font
 	Clip         *common.Player
+	Portrait     Portrait
+	Blip         Blip
 	Phases       map[string]BaddieState
 	Attacks      []Attack
+	MinWait      float32
+	MaxWait      float32
+	Targeting    TargetStrategy
 	StartPhase   string
 }
 
 type BaddieComponent struct {
-	Spritesheet *common.Spritesheet
-	Phases      map[string]BaddieState
+	Name         string
+	Spritesheet  *common.Spritesheet
+	Phases       map[string]BaddieState
+	CurrentPhase string
 }
 
 type BaddieFace interface {
@@ -50,8 +63,88 @@ type Baddie struct {
 	BaddieComponent
 	ChatComponent
 	AIComponent
+	StatusEffectComponent
+}
+
+func (b *Baddie) GetBaddie() *Baddie {
+	return b
+}
+
+// SetPhase ends the baddie's current phase and starts the named one.
+func (b *Baddie) SetPhase(name string) {
+	if cur, ok := b.Phases[b.CurrentPhase]; ok && cur.PhaseEndFunc != nil {
+		cur.PhaseEndFunc(b)
+	}
+	b.CurrentPhase = name
+	if next, ok := b.Phases[name]; ok && next.PhaseStartFunc != nil {
+		next.PhaseStartFunc(b)
+	}
+}
+
+func (b *Baddie) Move(p engo.Point) {
+	b.spr.Position = p
+	b.hpBar.Position = engo.Point{X: p.X, Y: p.Y + b.spr.Height + 4}
+	b.castBar.Position = engo.Point{X: p.X, Y: p.Y + b.spr.Height + 19}
+}
+
+// chat is msg in the baddie's voice, for the log.
+func (b *Baddie) chat(msg string) CombatLogMessage {
+	return b.logMessage(b.Name, moodFace(b.StatsComponent), msg)
 }
 
-func AddBaddie(bad BaddieInfo) *Baddie {
-	return nil
+func AddBaddie(info BaddieInfo, w *ecs.World) *Baddie {
+	bad := &Baddie{BasicEntity: ecs.NewBasic()}
+	bad.Name = info.Name
+	bad.totalCastTime = 1
+	bad.StatsComponent = StatsComponent{
+		HP:    info.HP,
+		MP:    info.MP,
+		MaxHP: info.MaxHP,
+		MaxMP: info.MaxMP,
+		Str:   info.Str,
+		Def:   info.Def,
+		Int:   info.Int,
+		Dex:   info.Dex,
+	}
+	bad.Spritesheet = common.NewSpritesheetWithBorderFromFile(info.Spritesheet, info.CellWidth, info.CellHeight, 1, 1)
+	bad.Phases = info.Phases
+	bad.Attacks = info.Attacks
+	bad.MinWait = info.MinWait
+	bad.MaxWait = info.MaxWait
+	bad.Targeting = info.Targeting
+	bad.Font = info.Font
+	bad.Clip = info.Clip
+	bad.Portrait = info.Portrait
+	bad.Blip = info.Blip
+	scale := info.Scale
+	if scale.X == 0 && scale.Y == 0 {
+		scale = engo.Point{X: 1, Y: 1}
+	}
+	bad.spr = &sprite{BasicEntity: ecs.NewBasic()}
+	bad.spr.Drawable = bad.Spritesheet.Drawable(0)
+	bad.spr.Scale = scale
+	bad.spr.Width = bad.spr.Drawable.Width() * scale.X
+	bad.spr.Height = bad.spr.Drawable.Height() * scale.Y
+	bad.spr.SetZIndex(1)
+	w.AddEntity(bad.spr)
+	bad.hpBar = &sprite{BasicEntity: ecs.NewBasic()}
+	bad.hpBar.Drawable = common.Rectangle{}
+	bad.hpBar.Color = color.RGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}
+	bad.hpBar.Width = bad.spr.Width
+	bad.hpBar.Height = 13
+	bad.hpBar.SetZIndex(1)
+	w.AddEntity(bad.hpBar)
+	bad.castBar = &sprite{BasicEntity: ecs.NewBasic()}
+	bad.castBar.Drawable = common.Rectangle{}
+	bad.castBar.Color = color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF}
+	bad.castBar.Width = 0
+	bad.castBar.Height = 13
+	bad.castBar.SetZIndex(1)
+	w.AddEntity(bad.castBar)
+	bad.Move(engo.Point{X: 320 - bad.spr.Width/2, Y: 80})
+	w.AddEntity(bad)
+	if info.StartPhase != "" {
+		bad.SetPhase(info.StartPhase)
+	}
+	return bad
 }
@@ -2,25 +2,134 @@ package main
 
 import (
 	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
 )
 
+var CastStartMessageType = "Cast Start Message"
+
+// CastStartMessage asks the BarSystem to start casting whatever the character
+// or baddie has selected at its targets.
+type CastStartMessage struct {
+	Chara  *Character
+	Baddie *Baddie
+}
+
+func (CastStartMessage) Type() string { return CastStartMessageType }
+
 type CastBarComponent struct {
 	barHP, barMP                   float32
 	totalCastTime, currentCastTime float32
+	reservedMP                     float32
+	reservedItem                   ItemID
 	isCasting                      bool
 }
 
+// DefaultCastTime is used when an ability or attack doesn't set its own cast
+// time. Quick and smart casters are faster, expensive spells are slower.
+func DefaultCastTime(stats StatsComponent, mpCost float32) float32 {
+	t := 2 + mpCost/10 - (stats.Dex+stats.Int)/100
+	if t < 0.5 {
+		t = 0.5
+	}
+	return t
+}
+
+// CancelCast stops the character's cast and gives back the MP it reserved.
+func (c *Character) CancelCast() {
+	if !c.isCasting {
+		return
+	}
+	c.MP += c.reservedMP
+	c.reservedMP = 0
+	if c.reservedItem != "" {
+		CurrentSave.AddToStash(c.reservedItem, 1)
+		c.reservedItem = ""
+	}
+	c.isCasting = false
+	c.currentCastTime = 0
+	c.totalCastTime = 1
+	c.SelectedAbility = Ability{}
+	c.IsAbilitySelected = false
+	c.SelectedItem = Item{}
+	c.IsItemSelected = false
+	c.TargetPlayers = make([]*Character, 0)
+	c.TargetBaddies = make([]*Baddie, 0)
+}
+
+// CancelCast stops the baddie's cast and gives back the MP it reserved.
+func (b *Baddie) CancelCast() {
+	if !b.isCasting {
+		return
+	}
+	b.MP += b.reservedMP
+	b.reservedMP = 0
+	b.isCasting = false
+	b.currentCastTime = 0
+	b.totalCastTime = 1
+	b.SelectedAttack = Attack{}
+	b.TargetPlayers = make([]*Character, 0)
+	b.TargetBaddies = make([]*Baddie, 0)
+}
+
+// maxStatusIcons is how many status icons fit beside a card.
+const maxStatusIcons = 5
+
 type barEntity struct {
 	chara  *Character
 	baddie *Baddie
+	icons  []*sprite
 }
 
 type BarSystem struct {
-	entities []barEntity
+	StatusIconURL string
+
+	entities  []barEntity
+	world     *ecs.World
+	iconSheet *common.Spritesheet
+	paused    bool
+}
+
+func (s *BarSystem) New(w *ecs.World) {
+	s.world = w
+	if s.StatusIconURL != "" {
+		s.iconSheet = common.NewSpritesheetWithBorderFromFile(s.StatusIconURL, 16, 16, 1, 1)
+	}
+	engo.Mailbox.Listen(WorldPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(WorldPauseMessage)
+		if !ok {
+			return
+		}
+		s.paused = msg.Pause
+	})
+	engo.Mailbox.Listen(CastStartMessageType, func(message engo.Message) {
+		msg, ok := message.(CastStartMessage)
+		if !ok {
+			return
+		}
+		if msg.Chara != nil {
+			s.startCharacterCast(msg.Chara)
+		}
+		if msg.Baddie != nil {
+			s.startBaddieCast(msg.Baddie)
+		}
+	})
 }
 
 func (s *BarSystem) Add(chara *Character, bad *Baddie) {
-	s.entities = append(s.entities, barEntity{chara, bad})
+	e := barEntity{chara: chara, baddie: bad}
+	if s.iconSheet != nil {
+		for i := 0; i < maxStatusIcons; i++ {
+			icon := &sprite{BasicEntity: ecs.NewBasic()}
+			icon.Width = 16
+			icon.Height = 16
+			icon.SetZIndex(2)
+			icon.Hidden = true
+			s.world.AddEntity(icon)
+			e.icons = append(e.icons, icon)
+		}
+	}
+	s.entities = append(s.entities, e)
 }
 
 func (s *BarSystem) AddByInterface(i ecs.Identifier) {
@@ -50,11 +159,17 @@ func (s *BarSystem) Remove(b ecs.BasicEntity) {
 		}
 	}
 	if d >= 0 {
+		for _, icon := range s.entities[d].icons {
+			s.world.RemoveEntity(icon.BasicEntity)
+		}
 		s.entities = append(s.entities[:d], s.entities[d+1:]...)
 	}
 }
 
 func (s *BarSystem) Update(dt float32) {
+	if s.paused {
+		return
+	}
 	for _, e := range s.entities {
 		if e.chara != nil {
 			if e.chara.barHP != e.chara.HP {
@@ -65,24 +180,163 @@ func (s *BarSystem) Update(dt float32) {
 				e.chara.barMP = e.chara.MP
 				e.chara.mpBar.Width = 83 * (e.chara.barMP / e.chara.MaxMP)
 			}
-			if e.chara.isCasting {
-				e.chara.currentCastTime += dt
+			s.logExpired(e.chara.tickStatus(dt, &e.chara.StatsComponent), e.chara.Name, e.chara.ChatComponent)
+			if e.chara.isCasting && !e.chara.Stunned() {
+				e.chara.currentCastTime += dt * e.chara.CastSpeed()
 				if e.chara.currentCastTime >= e.chara.totalCastTime {
 					e.chara.currentCastTime = 0
 					e.chara.totalCastTime = 1
-					if e.chara.SelectedAbility.EffectFunc != nil {
+					e.chara.reservedMP = 0
+					e.chara.isCasting = false
+					s.logExpired(e.chara.endTurn(), e.chara.Name, e.chara.ChatComponent)
+					if e.chara.IsItemSelected {
+						e.chara.reservedItem = ""
+						engo.Mailbox.Dispatch(e.chara.chat(e.chara.Name + " uses the " + e.chara.SelectedItem.Title + "!"))
+						if e.chara.SelectedItem.EffectFunc != nil {
+							e.chara.SelectedItem.EffectFunc(e.chara, e.chara.TargetPlayers, e.chara.TargetBaddies)
+						}
+						e.chara.SelectedItem = Item{}
+						e.chara.IsItemSelected = false
+						e.chara.TargetPlayers = make([]*Character, 0)
+						e.chara.TargetBaddies = make([]*Baddie, 0)
+					} else if e.chara.SelectedAbility.EffectFunc != nil {
+						hps := make([]float32, len(e.chara.TargetBaddies))
+						for i, bad := range e.chara.TargetBaddies {
+							hps[i] = bad.HP
+						}
 						e.chara.SelectedAbility.EffectFunc(e.chara, e.chara.TargetPlayers, e.chara.TargetBaddies)
+						for i, bad := range e.chara.TargetBaddies {
+							bad.AddThreat(e.chara, 1+hps[i]-bad.HP)
+						}
 						e.chara.SelectedAbility = Ability{}
+						e.chara.IsAbilitySelected = false
 						e.chara.TargetPlayers = make([]*Character, 0)
 						e.chara.TargetBaddies = make([]*Baddie, 0)
 					}
 				}
 				e.chara.castBar.Width = 83 * (e.chara.currentCastTime / e.chara.totalCastTime)
-			} else {
+			} else if !e.chara.isCasting {
 				e.chara.castBar.Width = 0
 			}
+			s.showIcons(e.icons, e.chara.Effects, e.chara.card.Hidden, engo.Point{
+				X: e.chara.card.Position.X + e.chara.card.Width + 2,
+				Y: e.chara.card.Position.Y,
+			}, engo.Point{Y: 18})
 		} else if e.baddie != nil {
+			if e.baddie.barHP != e.baddie.HP {
+				e.baddie.barHP = e.baddie.HP
+				e.baddie.hpBar.Width = e.baddie.spr.Width * (e.baddie.barHP / e.baddie.MaxHP)
+			}
+			s.logExpired(e.baddie.tickStatus(dt, &e.baddie.StatsComponent), "The "+e.baddie.Name, e.baddie.ChatComponent)
+			if e.baddie.isCasting && !e.baddie.Stunned() {
+				e.baddie.currentCastTime += dt * e.baddie.CastSpeed()
+				if e.baddie.currentCastTime >= e.baddie.totalCastTime {
+					e.baddie.currentCastTime = 0
+					e.baddie.totalCastTime = 1
+					e.baddie.reservedMP = 0
+					e.baddie.isCasting = false
+					s.logExpired(e.baddie.endTurn(), "The "+e.baddie.Name, e.baddie.ChatComponent)
+					if e.baddie.SelectedAttack.EffectFunc != nil {
+						e.baddie.SelectedAttack.EffectFunc(e.baddie, e.baddie.TargetPlayers, e.baddie.TargetBaddies)
+					}
+					e.baddie.SelectedAttack = Attack{}
+					e.baddie.TargetPlayers = make([]*Character, 0)
+					e.baddie.TargetBaddies = make([]*Baddie, 0)
+				}
+				e.baddie.castBar.Width = e.baddie.spr.Width * (e.baddie.currentCastTime / e.baddie.totalCastTime)
+			} else if !e.baddie.isCasting {
+				e.baddie.castBar.Width = 0
+			}
+			s.showIcons(e.icons, e.baddie.Effects, e.baddie.spr.Hidden, engo.Point{
+				X: e.baddie.spr.Position.X,
+				Y: e.baddie.spr.Position.Y - 18,
+			}, engo.Point{X: 18})
+		}
+	}
+}
+
+func (s *BarSystem) logExpired(expired []StatusEffect, name string, chat ChatComponent) {
+	for _, e := range expired {
+		engo.Mailbox.Dispatch(CombatLogMessage{
+			Msg:  e.expireMessage(name),
+			Fnt:  chat.Font,
+			Clip: chat.Clip,
+		})
+	}
+}
 
+// showIcons lines the status icons up from start, step apart.
+func (s *BarSystem) showIcons(icons []*sprite, effects []StatusEffect, hidden bool, start, step engo.Point) {
+	for i, icon := range icons {
+		if hidden || i >= len(effects) {
+			icon.Hidden = true
+			continue
 		}
+		icon.Drawable = s.iconSheet.Drawable(effects[i].Icon)
+		icon.Position = engo.Point{
+			X: start.X + step.X*float32(i),
+			Y: start.Y + step.Y*float32(i),
+		}
+		icon.Hidden = false
+	}
+}
+
+func (s *BarSystem) startCharacterCast(chara *Character) {
+	var cost float32
+	title := chara.SelectedItem.Title
+	if !chara.IsItemSelected {
+		cost = chara.SelectedAbility.MPCost
+		title = chara.SelectedAbility.Title
+	}
+	if cost > chara.MP {
+		engo.Mailbox.Dispatch(chara.chat(chara.Name + " doesn't have enough MP for " + title))
+		chara.SelectedAbility = Ability{}
+		chara.IsAbilitySelected = false
+		chara.SelectedItem = Item{}
+		chara.IsItemSelected = false
+		chara.TargetPlayers = make([]*Character, 0)
+		chara.TargetBaddies = make([]*Baddie, 0)
+		return
+	}
+	if chara.IsItemSelected && !chara.SelectedItem.KeyItem {
+		if !CurrentSave.RemoveFromStash(chara.SelectedItem.ID, 1) {
+			engo.Mailbox.Dispatch(chara.chat("There aren't any " + chara.SelectedItem.Title + "s left!"))
+			chara.SelectedItem = Item{}
+			chara.IsItemSelected = false
+			chara.TargetPlayers = make([]*Character, 0)
+			chara.TargetBaddies = make([]*Baddie, 0)
+			return
+		}
+		chara.reservedItem = chara.SelectedItem.ID
+	}
+	chara.MP -= cost
+	chara.reservedMP = cost
+	chara.currentCastTime = 0
+	chara.totalCastTime = 0
+	if !chara.IsItemSelected && chara.SelectedAbility.CastTimeFunc != nil {
+		chara.SelectedAbility.CastTimeFunc(chara)
+	}
+	if chara.totalCastTime <= 0 {
+		chara.totalCastTime = DefaultCastTime(chara.Stats(), cost)
+	}
+	chara.isCasting = true
+}
+
+func (s *BarSystem) startBaddieCast(bad *Baddie) {
+	cost := bad.SelectedAttack.MPCost
+	if cost > bad.MP {
+		engo.Mailbox.Dispatch(bad.chat("The " + bad.Name + " is out of MP!"))
+		bad.SelectedAttack = Attack{}
+		bad.TargetPlayers = make([]*Character, 0)
+		bad.TargetBaddies = make([]*Baddie, 0)
+		return
+	}
+	bad.MP -= cost
+	bad.reservedMP = cost
+	bad.currentCastTime = 0
+	bad.totalCastTime = bad.SelectedAttack.AttackTime
+	if bad.totalCastTime <= 0 {
+		bad.totalCastTime = DefaultCastTime(bad.Stats(), cost)
 	}
+	bad.isCasting = true
 }
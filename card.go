@@ -80,42 +80,58 @@ func (s *CardSelectSystem) Update(dt float32) {
 		s.entities[s.curIdx].MoveCard(engo.Point{X: s.entities[s.curIdx].card.Position.X, Y: s.entities[s.curIdx].card.Position.Y - 10})
 		s.setIdx = s.curIdx
 	}
-	if engo.Input.Button("left").JustPressed() {
-		s.curIdx--
-		if s.curIdx < 0 {
-			s.curIdx = len(s.entities) - 1
-		}
-	} else if engo.Input.Button("right").JustPressed() {
-		s.curIdx++
-		if s.curIdx > len(s.entities)-1 {
-			s.curIdx = 0
-		}
+	if button("left").JustPressed() {
+		s.next(-1)
+	} else if button("right").JustPressed() {
+		s.next(1)
 	}
-	if engo.Input.Button("A").JustPressed() {
+	if s.entities[s.setIdx].isCasting || s.entities[s.setIdx].Stunned() || s.entities[s.setIdx].IsKnockedOut {
+		return
+	}
+	if button("A").JustPressed() {
 		engo.Mailbox.Dispatch(PhaseSetMessage{
 			Phase: AbilitySelectPhase,
 		})
 		engo.Mailbox.Dispatch(PhaseDequeuMessage{})
-	} else if engo.Input.Button("B").JustPressed() {
+	} else if button("B").JustPressed() {
 		engo.Mailbox.Dispatch(PhaseSetMessage{
 			Phase: ItemSelectPhase,
 		})
 		engo.Mailbox.Dispatch(PhaseDequeuMessage{})
-	} else if engo.Input.Button("X").JustPressed() {
+	} else if button("X").JustPressed() {
 		s.entities[s.setIdx].SelectedAbility = RegularAttackAbility
+		s.entities[s.setIdx].IsAbilitySelected = false
+		s.entities[s.setIdx].IsItemSelected = false
 		engo.Mailbox.Dispatch(PhaseSetMessage{
 			Phase: TargetPhase,
 		})
 		engo.Mailbox.Dispatch(PhaseDequeuMessage{})
-	} else if engo.Input.Button("Y").JustPressed() {
+	} else if button("Y").JustPressed() {
 		s.entities[s.setIdx].SelectedAbility = DefendAbility
+		s.entities[s.setIdx].IsAbilitySelected = false
+		s.entities[s.setIdx].IsItemSelected = false
 		engo.Mailbox.Dispatch(PhaseSetMessage{
-			Phase: CardSelectPhase,
+			Phase: TargetPhase,
 		})
 		engo.Mailbox.Dispatch(PhaseDequeuMessage{})
 	}
 }
 
+// next moves the selection d cards over, skipping anyone knocked out.
+func (s *CardSelectSystem) next(d int) {
+	for range s.entities {
+		s.curIdx += d
+		if s.curIdx < 0 {
+			s.curIdx = len(s.entities) - 1
+		} else if s.curIdx > len(s.entities)-1 {
+			s.curIdx = 0
+		}
+		if !s.entities[s.curIdx].IsKnockedOut {
+			return
+		}
+	}
+}
+
 func (s *CardSelectSystem) pause() {
 	s.paused = true
 	for _, e := range s.entities {
@@ -124,8 +140,6 @@ func (s *CardSelectSystem) pause() {
 		e.hpBar.Hidden = true
 		e.mpBar.Hidden = true
 		e.castBar.Hidden = true
-		e.totalCastTime = 1
-		e.currentCastTime = 0
 	}
 	if s.setIdx >= 0 {
 		s.entities[s.setIdx].MoveCard(engo.Point{X: s.entities[s.setIdx].card.Position.X, Y: s.entities[s.setIdx].card.Position.Y + 10})
@@ -145,5 +159,8 @@ func (s *CardSelectSystem) unpause() {
 		e.castBar.Hidden = false
 		e.IsCardSelected = false
 	}
-	s.entities[0].IsCardSelected = true
+	if s.entities[s.curIdx].IsKnockedOut {
+		s.next(1)
+	}
+	s.entities[s.curIdx].IsCardSelected = true
 }
@@ -19,13 +19,18 @@ type CharacterInfo struct {
 	Dex, Int      float32
 	Font          *common.Font
 	Clip          *common.Player
+	Portrait      Portrait
+	Blip          Blip
 }
 
 type Item struct {
+	ID          ItemID
 	Title       string
 	Shorthand   string
 	Description string
 	Quantity    int
+	MaxStack    int  // 0 means the party can carry any number
+	KeyItem     bool // key items can be used but never get used up
 	TargetType  Target
 	EffectFunc  func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie)
 }
@@ -52,6 +57,7 @@ type StatsComponent struct {
 type StatusComponent struct {
 	Name           string
 	IsCardSelected bool
+	IsKnockedOut   bool
 	TextScale      engo.Point
 }
 
@@ -73,9 +79,11 @@ type InventoryComponent struct {
 }
 
 type ChatComponent struct {
-	Font *common.Font
-	Clip *common.Player
-	Dot  *common.Drawable
+	Font     *common.Font
+	Clip     *common.Player
+	Dot      *common.Drawable
+	Portrait Portrait
+	Blip     Blip
 }
 
 type Characterface interface {
@@ -99,6 +107,7 @@ type Character struct {
 	StatsComponent
 	CastBarComponent
 	StatusComponent
+	StatusEffectComponent
 	TargetComponent
 	AbilityComponent
 	InventoryComponent
@@ -131,26 +140,28 @@ func (c *Character) RemoveAbility(title string) {
 	}
 }
 
-func (c *Character) AddItem(i Item) {
-	for _, i2 := range c.Inventory {
-		if i.Title == i2.Title {
-			i2.Quantity += i.Quantity
-			return
-		}
-	}
-	c.Inventory = append(c.Inventory, i)
+// AddItem puts i.Quantity of the item in the party stash, up to its
+// MaxStack, and returns how many actually fit.
+func (c *Character) AddItem(i Item) int {
+	n := CurrentSave.AddToStash(i.ID, i.Quantity)
+	c.RefreshInventory()
+	return n
 }
 
-func (c *Character) RemoveItem(title string, quantity int) {
-	for i, item := range c.Inventory {
-		if item.Title == title {
-			item.Quantity -= quantity
-			if item.Quantity <= 0 {
-				c.Inventory = append(c.Inventory[:i], c.Inventory[i+1:]...)
-			}
-			return
-		}
+// RemoveItem takes quantity of the item out of the party stash. Key items
+// and items the party doesn't have enough of are left alone.
+func (c *Character) RemoveItem(id ItemID, quantity int) bool {
+	if item, ok := ItemByID(id); !ok || item.KeyItem {
+		return false
 	}
+	ok := CurrentSave.RemoveFromStash(id, quantity)
+	c.RefreshInventory()
+	return ok
+}
+
+// chat is msg in the character's voice, for the log.
+func (c *Character) chat(msg string) CombatLogMessage {
+	return c.logMessage(c.Name, moodFace(c.StatsComponent), msg)
 }
 
 func (c *Character) MoveCard(p engo.Point) {
@@ -220,6 +231,8 @@ func AddCharacter(info CharacterInfo, w *ecs.World) *Character {
 	w.AddEntity(chara.box)
 	chara.Font = info.Font
 	chara.Clip = info.Clip
+	chara.Portrait = info.Portrait
+	chara.Blip = info.Blip
 	w.AddEntity(chara)
 	return chara
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+// CombatRand is what abilities and attacks roll with. Swap in a seeded one to
+// replay a fight exactly.
+var CombatRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+const (
+	// DodgePenalty is how much harder it is to dodge a regular hit than to
+	// win an even Dex contest.
+	DodgePenalty float32 = 50
+	// CritMultiplier is how much extra a critical hit deals.
+	CritMultiplier float32 = 1.5
+)
+
+// Hit is the result of an attack roll.
+type Hit struct {
+	Damage int
+	Crit   bool
+	Dodged bool
+}
+
+// Contest rolls d100 plus a against d100 plus b and says if a won.
+func Contest(rng *rand.Rand, a, b float32) bool {
+	return rng.Intn(100)+1+int(a) > rng.Intn(100)+1+int(b)
+}
+
+// Check rolls d100 plus stat and says if it beat difficulty.
+func Check(rng *rand.Rand, stat, difficulty float32) bool {
+	return rng.Intn(100)+1+int(stat) > int(difficulty)
+}
+
+// Dodges rolls whether def gets out of the way of atk's attack.
+func Dodges(rng *rand.Rand, atk, def StatsComponent) bool {
+	return Contest(rng, def.Dex, atk.Dex+DodgePenalty)
+}
+
+// Crits rolls whether atk lands a critical hit. 1 in 5 per 100 Dex.
+func Crits(rng *rand.Rand, atk StatsComponent) bool {
+	return rng.Float32()*500 < atk.Dex
+}
+
+// Mitigate scales dmg down by a defense stat. 25 defense takes a fifth off,
+// 100 takes half.
+func Mitigate(dmg, defense float32) float32 {
+	if defense <= 0 {
+		return dmg
+	}
+	return dmg * 100 / (100 + defense)
+}
+
+// PhysicalDamage is base plus up to spread plus atk's Str, mitigated by def's
+// Def.
+func PhysicalDamage(rng *rand.Rand, atk, def StatsComponent, base float32, spread int) int {
+	return rollDamage(rng, base+atk.Str, spread, def.Def)
+}
+
+// MagicalDamage is base plus up to spread plus atk's Int, mitigated by the
+// average of def's Def and Int.
+func MagicalDamage(rng *rand.Rand, atk, def StatsComponent, base float32, spread int) int {
+	return rollDamage(rng, base+atk.Int, spread, (def.Def+def.Int)/2)
+}
+
+func rollDamage(rng *rand.Rand, base float32, spread int, defense float32) int {
+	dmg := base
+	if spread > 0 {
+		dmg += float32(rng.Intn(spread + 1))
+	}
+	dmg = Mitigate(dmg, defense)
+	if dmg < 1 {
+		return 1
+	}
+	return int(dmg + 0.5)
+}
+
+// PhysicalHit rolls a dodge, damage and crit for a physical attack.
+func PhysicalHit(rng *rand.Rand, atk, def StatsComponent, base float32, spread int) Hit {
+	if Dodges(rng, atk, def) {
+		return Hit{Dodged: true}
+	}
+	return crit(rng, atk, PhysicalDamage(rng, atk, def, base, spread))
+}
+
+// MagicalHit rolls a dodge, damage and crit for a magical attack.
+func MagicalHit(rng *rand.Rand, atk, def StatsComponent, base float32, spread int) Hit {
+	if Dodges(rng, atk, def) {
+		return Hit{Dodged: true}
+	}
+	return crit(rng, atk, MagicalDamage(rng, atk, def, base, spread))
+}
+
+func crit(rng *rand.Rand, atk StatsComponent, dmg int) Hit {
+	if Crits(rng, atk) {
+		return Hit{Damage: int(float32(dmg) * CritMultiplier), Crit: true}
+	}
+	return Hit{Damage: dmg}
+}
+
+// HealAmount is base plus up to spread plus half the healer's Int.
+func HealAmount(rng *rand.Rand, healer StatsComponent, base float32, spread int) int {
+	amt := base + healer.Int/2
+	if spread > 0 {
+		amt += float32(rng.Intn(spread + 1))
+	}
+	return int(amt + 0.5)
+}
+
+// ApplyDamage takes dmg off of HP, stopping at 0.
+func ApplyDamage(stats *StatsComponent, dmg int) {
+	stats.HP -= float32(dmg)
+	if stats.HP < 0 {
+		stats.HP = 0
+	}
+}
+
+// ApplyHeal adds amt to HP, stopping at MaxHP, and returns how much was
+// actually healed.
+func ApplyHeal(stats *StatsComponent, amt int) int {
+	before := stats.HP
+	stats.HP += float32(amt)
+	if stats.HP > stats.MaxHP {
+		stats.HP = stats.MaxHP
+	}
+	return int(stats.HP - before)
+}
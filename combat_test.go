@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCombatRolls(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var (
+		nobody = StatsComponent{}
+		brute  = StatsComponent{Str: 20}
+		mage   = StatsComponent{Int: 30}
+		tank   = StatsComponent{Def: 100, Int: 50}
+		blur   = StatsComponent{Dex: 1000}
+		sharp  = StatsComponent{Dex: 500, Str: 10}
+	)
+	b := func(ok bool) float32 {
+		if ok {
+			return 1
+		}
+		return 0
+	}
+
+	for _, tt := range []struct {
+		name      string
+		got, want float32
+	}{
+		// Contest can't be lost when a is ahead by more than the dice.
+		{"contest won", b(Contest(rng, 200, 0)), 1},
+		{"contest lost", b(Contest(rng, 0, 200)), 0},
+		{"check passed", b(Check(rng, 40, 35)), 1},
+		{"check failed", b(Check(rng, -100, 35)), 0},
+
+		{"zero defense", Mitigate(40, 0), 40},
+		{"negative defense", Mitigate(40, -10), 40},
+		{"25 defense", Mitigate(50, 25), 40},
+		{"100 defense", Mitigate(40, 100), 20},
+
+		{"guaranteed dodge", b(Dodges(rng, nobody, blur)), 1},
+		{"guaranteed hit", b(Dodges(rng, blur, nobody)), 0},
+
+		{"guaranteed crit", b(Crits(rng, sharp)), 1},
+		{"never crits", b(Crits(rng, nobody)), 0},
+
+		{"physical adds Str", float32(PhysicalDamage(rng, brute, nobody, 10, 0)), 30},
+		{"physical uses Def", float32(PhysicalDamage(rng, brute, tank, 10, 0)), 15},
+		{"physical does at least 1", float32(PhysicalDamage(rng, nobody, tank, 0, 0)), 1},
+		{"magical adds Int", float32(MagicalDamage(rng, mage, nobody, 10, 0)), 40},
+		// Magic is mitigated by the average of Def and Int, 75 here.
+		{"magical uses Def and Int", float32(MagicalDamage(rng, mage, tank, 10, 0)), 23},
+
+		{"crit multiplier", float32(PhysicalHit(rng, sharp, nobody, 10, 0).Damage), 20 * CritMultiplier},
+		{"dodged hit does nothing", float32(PhysicalHit(rng, nobody, blur, 10, 0).Damage), 0},
+
+		{"heal adds half of Int", float32(HealAmount(rng, mage, 10, 0)), 25},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDamageSpread(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		dmg := PhysicalDamage(rng, StatsComponent{}, StatsComponent{}, 10, 4)
+		if dmg < 10 || dmg > 14 {
+			t.Fatalf("damage %v is outside of 10 to 14", dmg)
+		}
+		seen[dmg] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("only rolled %v of the 5 damage values", len(seen))
+	}
+}
+
+func TestApplyDamageAndHeal(t *testing.T) {
+	stats := StatsComponent{HP: 10, MaxHP: 50}
+	ApplyDamage(&stats, 25)
+	if stats.HP != 0 {
+		t.Errorf("HP went to %v, want it to stop at 0", stats.HP)
+	}
+
+	stats.HP = 45
+	if healed := ApplyHeal(&stats, 20); healed != 5 || stats.HP != 50 {
+		t.Errorf("healed %v to %v HP, want 5 to 50", healed, stats.HP)
+	}
+	if healed := ApplyHeal(&stats, 20); healed != 0 || stats.HP != 50 {
+		t.Errorf("healed %v at full HP, want 0", healed)
+	}
+}
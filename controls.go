@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/EngoEngine/engo"
+)
+
+// Bindings are the keys for each action. An action can have more than one
+// key, but a key only does one action.
+type Bindings map[string][]engo.Key
+
+// controlActions are the actions that can be bound, in the order the
+// controls menu shows them.
+var controlActions = []string{"up", "down", "left", "right", "A", "B", "X", "Y", "FullScreen", "Exit", "Controls", "Log"}
+
+// CurrentBindings are the bindings every scene registers.
+var CurrentBindings = DefaultBindings()
+
+// DefaultBindings are the keys the game starts with.
+func DefaultBindings() Bindings {
+	return Bindings{
+		"up":         {engo.KeyW, engo.KeyArrowUp},
+		"down":       {engo.KeyS, engo.KeyArrowDown},
+		"left":       {engo.KeyA, engo.KeyArrowLeft},
+		"right":      {engo.KeyD, engo.KeyArrowRight},
+		"A":          {engo.KeyJ, engo.KeyZ},
+		"B":          {engo.KeyK, engo.KeyX},
+		"X":          {engo.KeyL, engo.KeyC},
+		"Y":          {engo.KeySemicolon, engo.KeyV},
+		"FullScreen": {engo.KeyFour, engo.KeyF4},
+		"Exit":       {engo.KeyEscape},
+		"Controls":   {engo.KeyF1, engo.KeyTab},
+		"Log":        {engo.KeyF2, engo.KeyH},
+	}
+}
+
+// keyNames are what keys are called in the bindings file and the menu.
+var keyNames = []struct {
+	name string
+	key  engo.Key
+}{
+	{"Grave", engo.KeyGrave},
+	{"Dash", engo.KeyDash},
+	{"Apostrophe", engo.KeyApostrophe},
+	{"Semicolon", engo.KeySemicolon},
+	{"Equals", engo.KeyEquals},
+	{"Comma", engo.KeyComma},
+	{"Period", engo.KeyPeriod},
+	{"Slash", engo.KeySlash},
+	{"Backslash", engo.KeyBackslash},
+	{"Backspace", engo.KeyBackspace},
+	{"Tab", engo.KeyTab},
+	{"CapsLock", engo.KeyCapsLock},
+	{"Space", engo.KeySpace},
+	{"Enter", engo.KeyEnter},
+	{"Escape", engo.KeyEscape},
+	{"Insert", engo.KeyInsert},
+	{"PrintScreen", engo.KeyPrintScreen},
+	{"Delete", engo.KeyDelete},
+	{"PageUp", engo.KeyPageUp},
+	{"PageDown", engo.KeyPageDown},
+	{"Home", engo.KeyHome},
+	{"End", engo.KeyEnd},
+	{"Pause", engo.KeyPause},
+	{"ScrollLock", engo.KeyScrollLock},
+	{"ArrowLeft", engo.KeyArrowLeft},
+	{"ArrowRight", engo.KeyArrowRight},
+	{"ArrowDown", engo.KeyArrowDown},
+	{"ArrowUp", engo.KeyArrowUp},
+	{"LeftBracket", engo.KeyLeftBracket},
+	{"LeftShift", engo.KeyLeftShift},
+	{"LeftControl", engo.KeyLeftControl},
+	{"LeftSuper", engo.KeyLeftSuper},
+	{"LeftAlt", engo.KeyLeftAlt},
+	{"RightBracket", engo.KeyRightBracket},
+	{"RightShift", engo.KeyRightShift},
+	{"RightControl", engo.KeyRightControl},
+	{"RightSuper", engo.KeyRightSuper},
+	{"RightAlt", engo.KeyRightAlt},
+	{"Zero", engo.KeyZero},
+	{"One", engo.KeyOne},
+	{"Two", engo.KeyTwo},
+	{"Three", engo.KeyThree},
+	{"Four", engo.KeyFour},
+	{"Five", engo.KeyFive},
+	{"Six", engo.KeySix},
+	{"Seven", engo.KeySeven},
+	{"Eight", engo.KeyEight},
+	{"Nine", engo.KeyNine},
+	{"F1", engo.KeyF1},
+	{"F2", engo.KeyF2},
+	{"F3", engo.KeyF3},
+	{"F4", engo.KeyF4},
+	{"F5", engo.KeyF5},
+	{"F6", engo.KeyF6},
+	{"F7", engo.KeyF7},
+	{"F8", engo.KeyF8},
+	{"F9", engo.KeyF9},
+	{"F10", engo.KeyF10},
+	{"F11", engo.KeyF11},
+	{"F12", engo.KeyF12},
+	{"A", engo.KeyA},
+	{"B", engo.KeyB},
+	{"C", engo.KeyC},
+	{"D", engo.KeyD},
+	{"E", engo.KeyE},
+	{"F", engo.KeyF},
+	{"G", engo.KeyG},
+	{"H", engo.KeyH},
+	{"I", engo.KeyI},
+	{"J", engo.KeyJ},
+	{"K", engo.KeyK},
+	{"L", engo.KeyL},
+	{"M", engo.KeyM},
+	{"N", engo.KeyN},
+	{"O", engo.KeyO},
+	{"P", engo.KeyP},
+	{"Q", engo.KeyQ},
+	{"R", engo.KeyR},
+	{"S", engo.KeyS},
+	{"T", engo.KeyT},
+	{"U", engo.KeyU},
+	{"V", engo.KeyV},
+	{"W", engo.KeyW},
+	{"X", engo.KeyX},
+	{"Y", engo.KeyY},
+	{"Z", engo.KeyZ},
+	{"NumLock", engo.KeyNumLock},
+	{"NumMultiply", engo.KeyNumMultiply},
+	{"NumDivide", engo.KeyNumDivide},
+	{"NumAdd", engo.KeyNumAdd},
+	{"NumSubtract", engo.KeyNumSubtract},
+	{"NumZero", engo.KeyNumZero},
+	{"NumOne", engo.KeyNumOne},
+	{"NumTwo", engo.KeyNumTwo},
+	{"NumThree", engo.KeyNumThree},
+	{"NumFour", engo.KeyNumFour},
+	{"NumFive", engo.KeyNumFive},
+	{"NumSix", engo.KeyNumSix},
+	{"NumSeven", engo.KeyNumSeven},
+	{"NumEight", engo.KeyNumEight},
+	{"NumNine", engo.KeyNumNine},
+	{"NumDecimal", engo.KeyNumDecimal},
+	{"NumEnter", engo.KeyNumEnter},
+}
+
+func keyName(k engo.Key) string {
+	for _, n := range keyNames {
+		if n.key == k {
+			return n.name
+		}
+	}
+	return fmt.Sprintf("key %v", int(k))
+}
+
+func keyByName(name string) (engo.Key, bool) {
+	for _, n := range keyNames {
+		if strings.EqualFold(n.name, name) {
+			return n.key, true
+		}
+	}
+	return 0, false
+}
+
+// keyButton is the button registered for a single key, so the controls menu
+// can tell which key was pressed.
+func keyButton(k engo.Key) string {
+	return "key " + keyName(k)
+}
+
+// actionFor is the action k is bound to, or "" if it's free.
+func (b Bindings) actionFor(k engo.Key) string {
+	for _, action := range controlActions {
+		for _, bound := range b[action] {
+			if bound == k {
+				return action
+			}
+		}
+	}
+	return ""
+}
+
+// Toggle adds k to action, or takes it off if it's already there. It won't
+// take a key from another action or leave an action without any keys, and
+// says why in the error.
+func (b Bindings) Toggle(action string, k engo.Key) error {
+	keys := b[action]
+	for i, bound := range keys {
+		if bound != k {
+			continue
+		}
+		if len(keys) == 1 {
+			return fmt.Errorf("%v needs at least one key", action)
+		}
+		b[action] = append(keys[:i:i], keys[i+1:]...)
+		return nil
+	}
+	if other := b.actionFor(k); other != "" {
+		return fmt.Errorf("%v is already used for %v", keyName(k), other)
+	}
+	b[action] = append(keys[:len(keys):len(keys)], k)
+	return nil
+}
+
+// Describe lists an action's keys, like "W, ArrowUp".
+func (b Bindings) Describe(action string) string {
+	names := make([]string, len(b[action]))
+	for i, k := range b[action] {
+		names[i] = keyName(k)
+	}
+	return strings.Join(names, ", ")
+}
+
+// EncodeBindings writes b as JSON, with keys by name.
+func EncodeBindings(b Bindings) ([]byte, error) {
+	file := make(map[string][]string)
+	for action, keys := range b {
+		for _, k := range keys {
+			file[action] = append(file[action], keyName(k))
+		}
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// DecodeBindings reads bindings written by EncodeBindings. Actions missing
+// from the file keep their default keys.
+func DecodeBindings(data []byte) (Bindings, error) {
+	file := make(map[string][]string)
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	b := DefaultBindings()
+	var actions []string
+	for action := range file {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		if _, ok := b[action]; !ok {
+			return nil, fmt.Errorf("there's no action %q", action)
+		}
+		if len(file[action]) == 0 {
+			return nil, fmt.Errorf("%v needs at least one key", action)
+		}
+		var keys []engo.Key
+		for _, name := range file[action] {
+			k, ok := keyByName(name)
+			if !ok {
+				return nil, fmt.Errorf("%v: there's no key called %q", action, name)
+			}
+			keys = append(keys, k)
+		}
+		b[action] = keys
+	}
+	seen := make(map[engo.Key]string)
+	for _, action := range controlActions {
+		for _, k := range b[action] {
+			if other, ok := seen[k]; ok && other != action {
+				return nil, fmt.Errorf("%v is bound to both %v and %v", keyName(k), other, action)
+			}
+			seen[k] = action
+		}
+	}
+	return b, nil
+}
+
+// LoadBindings reads the bindings file into CurrentBindings. If there isn't
+// one, or it can't be read, the defaults are used.
+func LoadBindings() {
+	data, err := readConfigImpl("controls")
+	if err == nil && data != nil {
+		var b Bindings
+		if b, err = DecodeBindings(data); err == nil {
+			CurrentBindings = b
+			return
+		}
+	}
+	if err != nil {
+		log.Printf("Unable to load the controls, using the defaults. Error was: %v", err)
+	}
+	CurrentBindings = DefaultBindings()
+}
+
+// SaveBindings writes CurrentBindings to the bindings file.
+func SaveBindings() error {
+	data, err := EncodeBindings(CurrentBindings)
+	if err != nil {
+		return err
+	}
+	return writeConfigImpl("controls", data)
+}
+
+// RegisterControls registers CurrentBindings' buttons, along with a button
+// for every key for the controls menu. Every scene calls it in Preload.
+func RegisterControls() {
+	for _, action := range controlActions {
+		engo.Input.RegisterButton(action, CurrentBindings[action]...)
+	}
+	for _, n := range keyNames {
+		engo.Input.RegisterButton(keyButton(n.key), n.key)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+func TestBindingsRoundTrip(t *testing.T) {
+	b := DefaultBindings()
+	b["A"] = []engo.Key{engo.KeyJ, engo.KeyP}
+	b["Exit"] = []engo.Key{engo.KeyEscape, engo.KeyQ}
+	data, err := EncodeBindings(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeBindings(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("got %v back, want %v", got, b)
+	}
+}
+
+func TestDecodeBindings(t *testing.T) {
+	for _, tt := range []struct {
+		name, file string
+		// changed are the actions that don't have their default keys.
+		changed Bindings
+		err     string
+	}{
+		{name: "empty", file: `{}`},
+		{name: "one action", file: `{"up": ["I"]}`, changed: Bindings{"up": {engo.KeyI}}},
+		{name: "any case", file: `{"up": ["w", "ARROWUP"]}`},
+		{
+			name:    "moving a key",
+			file:    `{"up": ["S"], "down": ["I"]}`,
+			changed: Bindings{"up": {engo.KeyS}, "down": {engo.KeyI}},
+		},
+
+		{name: "not json", file: `up = W`, err: "invalid character"},
+		{name: "unknown action", file: `{"jump": ["Space"]}`, err: `no action "jump"`},
+		{name: "unknown key", file: `{"up": ["Pogo"]}`, err: `up: there's no key called "Pogo"`},
+		{name: "no keys", file: `{"up": []}`, err: "up needs at least one key"},
+		{name: "key for two actions", file: `{"up": ["S"]}`, err: "S is bound to both up and down"},
+		{name: "key for two changed actions", file: `{"A": ["P"], "B": ["P"]}`, err: "P is bound to both A and B"},
+	} {
+		got, err := DecodeBindings([]byte(tt.file))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%v: got error %v, want one saying %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tt.name, err)
+			continue
+		}
+		want := DefaultBindings()
+		for action, keys := range tt.changed {
+			want[action] = keys
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: got %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestBindingsToggle(t *testing.T) {
+	b := DefaultBindings()
+	for _, tt := range []struct {
+		name   string
+		action string
+		key    engo.Key
+		err    string
+		want   []engo.Key
+	}{
+		{"adding a free key", "up", engo.KeyI, "", []engo.Key{engo.KeyW, engo.KeyArrowUp, engo.KeyI}},
+		{"taking it off", "up", engo.KeyI, "", []engo.Key{engo.KeyW, engo.KeyArrowUp}},
+		{"stealing a key", "up", engo.KeyS, "S is already used for down", []engo.Key{engo.KeyW, engo.KeyArrowUp}},
+		{"taking off the first key", "up", engo.KeyW, "", []engo.Key{engo.KeyArrowUp}},
+		{"taking off the last key", "up", engo.KeyArrowUp, "up needs at least one key", []engo.Key{engo.KeyArrowUp}},
+		{"the only key", "Exit", engo.KeyEscape, "Exit needs at least one key", []engo.Key{engo.KeyEscape}},
+		{"a key that was taken off", "down", engo.KeyW, "", []engo.Key{engo.KeyS, engo.KeyArrowDown, engo.KeyW}},
+	} {
+		err := b.Toggle(tt.action, tt.key)
+		if tt.err == "" && err != nil {
+			t.Errorf("%v: %v", tt.name, err)
+		} else if tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("%v: got error %v, want %q", tt.name, err, tt.err)
+		}
+		if !reflect.DeepEqual(b[tt.action], tt.want) {
+			t.Errorf("%v: %v is bound to %v, want %v", tt.name, tt.action, b[tt.action], tt.want)
+		}
+	}
+	if got := b["down"]; !reflect.DeepEqual(got, []engo.Key{engo.KeyS, engo.KeyArrowDown, engo.KeyW}) {
+		t.Errorf("down lost its keys to up, bound to %v", got)
+	}
+
+	// Toggling a copy doesn't change the keys of the bindings it came from.
+	defaults := DefaultBindings()
+	c := Bindings{"up": defaults["up"][:1], "down": defaults["down"]}
+	if err := c.Toggle("up", engo.KeyI); err != nil {
+		t.Fatal(err)
+	}
+	if defaults["up"][1] != engo.KeyArrowUp {
+		t.Errorf("toggling a copy changed the original to %v", defaults["up"])
+	}
+}
@@ -144,7 +144,7 @@ func (s *CursorSystem) Remove(basic ecs.BasicEntity) {
 func (s *CursorSystem) Update(dt float32) {
 	for i := 0; i < len(s.entities); i++ {
 		if s.entities[i].Selected {
-			if engo.Input.Button("right").JustPressed() {
+			if button("right").JustPressed() {
 				if s.clickSound.IsPlaying() {
 					s.clickSound.Pause()
 				}
@@ -160,7 +160,7 @@ func (s *CursorSystem) Update(dt float32) {
 					s.setPointer(i + 1)
 					return
 				}
-			} else if engo.Input.Button("left").JustPressed() {
+			} else if button("left").JustPressed() {
 				if s.clickSound.IsPlaying() {
 					s.clickSound.Pause()
 				}
@@ -177,7 +177,7 @@ func (s *CursorSystem) Update(dt float32) {
 					return
 				}
 			}
-			if engo.Input.Button("up").JustPressed() {
+			if button("up").JustPressed() {
 				if s.clickSound.IsPlaying() {
 					s.clickSound.Pause()
 				}
@@ -193,7 +193,7 @@ func (s *CursorSystem) Update(dt float32) {
 					s.setPointer(i - s.jump)
 					return
 				}
-			} else if engo.Input.Button("down").JustPressed() {
+			} else if button("down").JustPressed() {
 				if s.clickSound.IsPlaying() {
 					s.clickSound.Pause()
 				}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/EngoEngine/engo/common"
+
+	"github.com/SkeleboyStudios/skeleIntro/assets"
+)
+
+// Voice is the font and sound a line of dialogue is said with.
+type Voice struct {
+	Font *common.Font
+	Clip *common.Player
+	// Name is who the log and backlog say is talking. Narration leaves it
+	// empty.
+	Name string
+	// Portrait is shown beside the log in its Face expression. Blip is
+	// played as lines are typed, instead of Clip if it has a pitch.
+	Portrait Portrait
+	Face     string
+	Blip     Blip
+}
+
+// DialogueAction is Go code a script can call with "do". It can return a
+// sequence to play next, or nil.
+type DialogueAction func(args []string) *Sequence
+
+// DialogueStep is one step of a dialogue script. Each step uses exactly one
+// of say, ask, if, choose, roll, pick, keypad, set, add, give or do.
+type DialogueStep struct {
+	// Say shows lines in Speaker's voice, or the default one, with their
+	// Face expression if they have a portrait.
+	Say     []string `json:"say,omitempty"`
+	Speaker string   `json:"speaker,omitempty"`
+	Face    string   `json:"face,omitempty"`
+	// Ask shows lines and the yes/no box, then plays Yes or No. With
+	// Options it shows those instead, Columns wide, and plays the Then of
+	// the one picked. An option is greyed out while its If doesn't hold.
+	// Default is where the cursor starts and Cancel is picked by B.
+	Ask     []string       `json:"ask,omitempty"`
+	Yes     []DialogueStep `json:"yes,omitempty"`
+	No      []DialogueStep `json:"no,omitempty"`
+	Options []DialogueStep `json:"options,omitempty"`
+	Columns int            `json:"columns,omitempty"`
+	Label   string         `json:"label,omitempty"`
+	Default bool           `json:"default,omitempty"`
+	Cancel  bool           `json:"cancel,omitempty"`
+	// If plays Then when its condition holds and Else when it doesn't.
+	// Conditions are save flags like "HasPPE" or "!HasPPE" and counters
+	// like "MarsChecks < 3", joined with "&&".
+	If   string         `json:"if,omitempty"`
+	Then []DialogueStep `json:"then,omitempty"`
+	Else []DialogueStep `json:"else,omitempty"`
+	// Choose plays the Then of the first entry whose If holds. An entry
+	// without an If always holds.
+	Choose []DialogueStep `json:"choose,omitempty"`
+	// Roll rolls a die with that many sides, playing Then if it comes up
+	// AtLeast or higher and Else if it doesn't.
+	Roll    int `json:"roll,omitempty"`
+	AtLeast int `json:"atLeast,omitempty"`
+	// Pick plays the Then of one entry at random. Entries with a higher
+	// Weight come up more often, and the Weight defaults to 1.
+	Pick   []DialogueStep `json:"pick,omitempty"`
+	Weight int            `json:"weight,omitempty"`
+	// Keypad opens the keypad for a code as long as this one, playing Then
+	// if it's typed in right and Else if it isn't.
+	Keypad string `json:"keypad,omitempty"`
+	// Set sets save flags and Add adds to save counters.
+	Set map[string]bool `json:"set,omitempty"`
+	Add map[string]int  `json:"add,omitempty"`
+	// Give puts items in the party stash, by item ID.
+	Give map[string]int `json:"give,omitempty"`
+	// Do calls the action with that name, passing it Args.
+	Do   string   `json:"do,omitempty"`
+	Args []string `json:"args,omitempty"`
+
+	cond []dialogueTerm
+}
+
+// Dialogue plays scripts from dialogue files in the asset bundle. A
+// dialogue file is a JSON object of script IDs to lists of DialogueSteps.
+// Set Voices and Actions before calling Load, since Load checks the
+// scripts only use ones that exist.
+type Dialogue struct {
+	// Voices are who can speak in a script. The "" voice is used when a
+	// step doesn't give a speaker.
+	Voices  map[string]Voice
+	Actions map[string]DialogueAction
+
+	scripts map[string][]DialogueStep
+}
+
+// Load adds the scripts in the dialogue file at url.
+func (d *Dialogue) Load(url string) error {
+	data, err := assets.Asset(url)
+	if err != nil {
+		return err
+	}
+	scripts := make(map[string][]DialogueStep)
+	if err := json.Unmarshal(data, &scripts); err != nil {
+		return fmt.Errorf("%v: %v", url, err)
+	}
+	for id, steps := range scripts {
+		if err := d.check(steps); err != nil {
+			return fmt.Errorf("%v: script %q: %v", url, id, err)
+		}
+	}
+	if d.scripts == nil {
+		d.scripts = make(map[string][]DialogueStep)
+	}
+	for id, steps := range scripts {
+		d.scripts[id] = steps
+	}
+	return nil
+}
+
+// Sequence builds the script with the given ID.
+func (d *Dialogue) Sequence(id string) *Sequence {
+	steps, ok := d.scripts[id]
+	if !ok {
+		log.Printf("There's no dialogue script with ID %q", id)
+	}
+	return d.build(steps)
+}
+
+// Play returns a func that plays the script with the given ID, for use as
+// an interest's Func.
+func (d *Dialogue) Play(id string) func() {
+	return func() {
+		d.Sequence(id).Play()
+	}
+}
+
+func (d *Dialogue) build(steps []DialogueStep) *Sequence {
+	v := d.Voices[""]
+	s := NewSequence(v.Font, v.Clip)
+	for _, step := range steps {
+		step := step
+		switch {
+		case step.Say != nil:
+			v := d.Voices[step.Speaker]
+			v.Face = step.Face
+			s.SayVoice(v, step.Say...)
+		case step.Ask != nil && step.Options != nil:
+			v := d.Voices[step.Speaker]
+			v.Face = step.Face
+			options := make([]Option, len(step.Options))
+			for i, e := range step.Options {
+				cond := e.cond
+				options[i] = Option{
+					Label:   e.Label,
+					Then:    d.build(e.Then),
+					Default: e.Default,
+					Cancel:  e.Cancel,
+					Disabled: func() bool {
+						return !conditionHolds(cond, CurrentSave)
+					},
+				}
+			}
+			s.ChooseVoice(v, step.Ask, step.Columns, options...)
+		case step.Ask != nil:
+			v := d.Voices[step.Speaker]
+			v.Face = step.Face
+			s.AskVoice(v, step.Ask, d.build(step.Yes), d.build(step.No))
+		case step.If != "":
+			s.Then(func() *Sequence {
+				if conditionHolds(step.cond, CurrentSave) {
+					return d.build(step.Then)
+				}
+				return d.build(step.Else)
+			})
+		case step.Choose != nil:
+			s.Then(func() *Sequence {
+				for _, e := range step.Choose {
+					if conditionHolds(e.cond, CurrentSave) {
+						return d.build(e.Then)
+					}
+				}
+				return nil
+			})
+		case step.Roll != 0:
+			s.Then(func() *Sequence {
+				if rand.Intn(step.Roll)+1 >= step.AtLeast {
+					return d.build(step.Then)
+				}
+				return d.build(step.Else)
+			})
+		case step.Pick != nil:
+			s.Then(func() *Sequence {
+				return d.build(pickWeighted(step.Pick, rand.Intn(totalWeight(step.Pick))))
+			})
+		case step.Keypad != "":
+			s.EnterCode(len(step.Keypad), func(code string) bool {
+				return code == step.Keypad
+			}, d.build(step.Then), d.build(step.Else))
+		case step.Set != nil:
+			s.Do(func() {
+				for name, value := range step.Set {
+					saveField(CurrentSave, name).SetBool(value)
+				}
+			})
+		case step.Add != nil:
+			s.Do(func() {
+				for name, n := range step.Add {
+					f := saveField(CurrentSave, name)
+					f.SetInt(f.Int() + int64(n))
+				}
+			})
+		case step.Give != nil:
+			s.Do(func() {
+				for id, n := range step.Give {
+					CurrentSave.AddToStash(ItemID(id), n)
+				}
+			})
+		case step.Do != "":
+			action := d.Actions[step.Do]
+			s.Then(func() *Sequence {
+				return action(step.Args)
+			})
+		}
+	}
+	return s
+}
+
+func (d *Dialogue) check(steps []DialogueStep) error {
+	for i := range steps {
+		if err := d.checkStep(&steps[i]); err != nil {
+			return fmt.Errorf("step %v: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// checkStep makes sure step can be played and parses its condition.
+func (d *Dialogue) checkStep(step *DialogueStep) error {
+	kinds := 0
+	for _, used := range []bool{
+		step.Say != nil, step.Ask != nil, step.If != "", step.Choose != nil,
+		step.Roll != 0, step.Pick != nil, step.Keypad != "", step.Set != nil,
+		step.Add != nil, step.Give != nil, step.Do != "",
+	} {
+		if used {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("a step needs exactly one of say, ask, if, choose, roll, pick, keypad, set, add, give or do")
+	}
+
+	var err error
+	switch {
+	case step.Say != nil, step.Ask != nil:
+		v, ok := d.Voices[step.Speaker]
+		if !ok {
+			return fmt.Errorf("there's no speaker %q", step.Speaker)
+		}
+		if _, ok := v.Portrait[step.Face]; step.Face != "" && !ok {
+			return fmt.Errorf("%q doesn't have a %q face", step.Speaker, step.Face)
+		}
+		if err = d.check(step.Yes); err == nil {
+			err = d.check(step.No)
+		}
+		if err != nil || step.Options == nil {
+			return err
+		}
+		if step.Yes != nil || step.No != nil {
+			return errors.New("ask can't have both yes/no and options")
+		}
+		for i := range step.Options {
+			e := &step.Options[i]
+			if e.Label == "" {
+				return fmt.Errorf("option %v needs a label", i+1)
+			}
+			if e.cond, err = parseCondition(e.If); err != nil {
+				return err
+			}
+			if err = d.check(e.Then); err != nil {
+				return err
+			}
+		}
+	case step.If != "":
+		if step.cond, err = parseCondition(step.If); err != nil {
+			return err
+		}
+		if err = d.check(step.Then); err == nil {
+			err = d.check(step.Else)
+		}
+	case step.Choose != nil:
+		for i := range step.Choose {
+			e := &step.Choose[i]
+			if e.cond, err = parseCondition(e.If); err != nil {
+				return err
+			}
+			if err = d.check(e.Then); err != nil {
+				return err
+			}
+		}
+	case step.Roll != 0:
+		if step.Roll < 0 {
+			return fmt.Errorf("can't roll a %v sided die", step.Roll)
+		}
+		if err = d.check(step.Then); err == nil {
+			err = d.check(step.Else)
+		}
+	case step.Pick != nil:
+		if totalWeight(step.Pick) <= 0 {
+			return errors.New("pick needs at least one entry with a weight")
+		}
+		for i := range step.Pick {
+			if step.Pick[i].Weight < 0 {
+				return errors.New("pick weights can't be negative")
+			}
+			if err = d.check(step.Pick[i].Then); err != nil {
+				return err
+			}
+		}
+	case step.Keypad != "":
+		if strings.Trim(step.Keypad, "0123456789") != "" {
+			return fmt.Errorf("keypad code %q can only have digits", step.Keypad)
+		}
+		if err = d.check(step.Then); err == nil {
+			err = d.check(step.Else)
+		}
+	case step.Set != nil:
+		for name := range step.Set {
+			if err = checkSaveField(name, reflect.Bool); err != nil {
+				return err
+			}
+		}
+	case step.Add != nil:
+		for name := range step.Add {
+			if err = checkSaveField(name, reflect.Int); err != nil {
+				return err
+			}
+		}
+	case step.Give != nil:
+		for id, n := range step.Give {
+			if _, ok := ItemByID(ItemID(id)); !ok {
+				return fmt.Errorf("there's no item %q", id)
+			}
+			if n <= 0 {
+				return fmt.Errorf("can't give %v of %q", n, id)
+			}
+		}
+	case step.Do != "":
+		if _, ok := d.Actions[step.Do]; !ok {
+			return fmt.Errorf("there's no action %q", step.Do)
+		}
+	}
+	return err
+}
+
+func weightOf(step DialogueStep) int {
+	if step.Weight == 0 {
+		return 1
+	}
+	return step.Weight
+}
+
+func totalWeight(entries []DialogueStep) int {
+	total := 0
+	for _, e := range entries {
+		total += weightOf(e)
+	}
+	return total
+}
+
+// pickWeighted returns the steps of the entry that r, from 0 up to the total
+// weight of entries, lands on.
+func pickWeighted(entries []DialogueStep, r int) []DialogueStep {
+	for _, e := range entries {
+		if r < weightOf(e) {
+			return e.Then
+		}
+		r -= weightOf(e)
+	}
+	return nil
+}
+
+// dialogueTerm is one part of a condition. With no op it checks a save flag,
+// otherwise it compares a save counter to value.
+type dialogueTerm struct {
+	field string
+	not   bool
+	op    string
+	value int
+}
+
+// dialogueOps are the comparisons a condition can make. The two character
+// ones go first so "<=" isn't read as "<".
+var dialogueOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// parseCondition parses a condition like "HasPPE && MarsChecks < 3". An
+// empty condition always holds.
+func parseCondition(s string) ([]dialogueTerm, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var terms []dialogueTerm
+	for _, part := range strings.Split(s, "&&") {
+		part = strings.TrimSpace(part)
+		t := dialogueTerm{}
+		for _, op := range dialogueOps {
+			i := strings.Index(part, op)
+			if i < 0 {
+				continue
+			}
+			v, err := strconv.Atoi(strings.TrimSpace(part[i+len(op):]))
+			if err != nil {
+				return nil, fmt.Errorf("can't compare %q to a number", part)
+			}
+			t.field, t.op, t.value = strings.TrimSpace(part[:i]), op, v
+			break
+		}
+		kind := reflect.Int
+		if t.op == "" {
+			t.field = strings.TrimSpace(strings.TrimPrefix(part, "!"))
+			t.not = strings.HasPrefix(part, "!")
+			kind = reflect.Bool
+		}
+		if err := checkSaveField(t.field, kind); err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// conditionHolds reports whether every term holds for save.
+func conditionHolds(terms []dialogueTerm, save *SaveData) bool {
+	for _, t := range terms {
+		f := saveField(save, t.field)
+		if t.op == "" {
+			if f.Bool() == t.not {
+				return false
+			}
+			continue
+		}
+		n := int(f.Int())
+		var ok bool
+		switch t.op {
+		case "<=":
+			ok = n <= t.value
+		case ">=":
+			ok = n >= t.value
+		case "==":
+			ok = n == t.value
+		case "!=":
+			ok = n != t.value
+		case "<":
+			ok = n < t.value
+		case ">":
+			ok = n > t.value
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSaveField makes sure SaveData has a field called name of the given
+// kind, a bool for flags or an int for counters.
+func checkSaveField(name string, kind reflect.Kind) error {
+	f, ok := reflect.TypeOf(SaveData{}).FieldByName(name)
+	if !ok || f.Type.Kind() != kind {
+		if kind == reflect.Bool {
+			return fmt.Errorf("%q isn't a save flag", name)
+		}
+		return fmt.Errorf("%q isn't a save counter", name)
+	}
+	return nil
+}
+
+func saveField(save *SaveData, name string) reflect.Value {
+	return reflect.ValueOf(save).Elem().FieldByName(name)
+}
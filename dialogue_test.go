@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testDialogue has the voices and actions the scene sets up, without
+// anything to draw or play them with.
+func testDialogue() *Dialogue {
+	faces := Portrait{}
+	for _, f := range partyFaces {
+		faces[f] = nil
+	}
+	d := &Dialogue{
+		Voices: map[string]Voice{
+			"":    {},
+			"you": {Name: "You", Portrait: faces},
+			"me":  {Name: "Me", Portrait: faces},
+			"len": {Name: "Len", Portrait: faces},
+		},
+		Actions: map[string]DialogueAction{},
+	}
+	for _, name := range []string{"visit", "animate", "crash", "update-safe", "ghost-fight"} {
+		d.Actions[name] = func([]string) *Sequence { return nil }
+	}
+	return d
+}
+
+func TestParseCondition(t *testing.T) {
+	for _, tt := range []struct {
+		cond string
+		want []dialogueTerm
+		err  string
+	}{
+		{cond: "", want: nil},
+		{cond: "  ", want: nil},
+		{cond: "HasPPE", want: []dialogueTerm{{field: "HasPPE"}}},
+		{cond: "!HasPPE", want: []dialogueTerm{{field: "HasPPE", not: true}}},
+		{cond: "! HasPPE", want: []dialogueTerm{{field: "HasPPE", not: true}}},
+		{cond: "MarsChecks < 3", want: []dialogueTerm{{field: "MarsChecks", op: "<", value: 3}}},
+		{cond: "MarsChecks<=3", want: []dialogueTerm{{field: "MarsChecks", op: "<=", value: 3}}},
+		{cond: "KeyCount >= 2", want: []dialogueTerm{{field: "KeyCount", op: ">=", value: 2}}},
+		{cond: "KeyCount == 4", want: []dialogueTerm{{field: "KeyCount", op: "==", value: 4}}},
+		{cond: "KeyCount != 4", want: []dialogueTerm{{field: "KeyCount", op: "!=", value: 4}}},
+		{cond: "KeyCount > -1", want: []dialogueTerm{{field: "KeyCount", op: ">", value: -1}}},
+		{
+			cond: "HasPPE && !IsSafeOpen && KeyCount >= 2",
+			want: []dialogueTerm{
+				{field: "HasPPE"},
+				{field: "IsSafeOpen", not: true},
+				{field: "KeyCount", op: ">=", value: 2},
+			},
+		},
+
+		{cond: "MarsChecks < three", err: "can't compare"},
+		{cond: "MarsChecks <", err: "can't compare"},
+		{cond: "HasPogs", err: `"HasPogs" isn't a save flag`},
+		{cond: "HasPPE < 3", err: `"HasPPE" isn't a save counter`},
+		{cond: "MarsChecks", err: `"MarsChecks" isn't a save flag`},
+		{cond: "PlayerLocation", err: "isn't a save flag"},
+		{cond: "HasPPE &&", err: `"" isn't a save flag`},
+		{cond: "HasPPE || HasSalt", err: "isn't a save flag"},
+	} {
+		got, err := parseCondition(tt.cond)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: got error %v, want one saying %q", tt.cond, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.cond, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %+v, want %+v", tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestConditionHolds(t *testing.T) {
+	save := NewSaveData()
+	save.HasPPE = true
+	save.MarsChecks = 2
+	for _, tt := range []struct {
+		cond string
+		want bool
+	}{
+		{"", true},
+		{"HasPPE", true},
+		{"!HasPPE", false},
+		{"HasSalt", false},
+		{"!HasSalt", true},
+		{"MarsChecks < 2", false},
+		{"MarsChecks < 3", true},
+		{"MarsChecks <= 2", true},
+		{"MarsChecks > 2", false},
+		{"MarsChecks >= 2", true},
+		{"MarsChecks == 2", true},
+		{"MarsChecks != 2", false},
+		{"HasPPE && MarsChecks == 2", true},
+		{"HasPPE && HasSalt", false},
+		{"HasSalt && HasPPE", false},
+	} {
+		terms, err := parseCondition(tt.cond)
+		if err != nil {
+			t.Errorf("%q: %v", tt.cond, err)
+			continue
+		}
+		if got := conditionHolds(terms, save); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	entry := func(say string, weight int) DialogueStep {
+		return DialogueStep{Weight: weight, Then: []DialogueStep{{Say: []string{say}}}}
+	}
+	// a defaults to a weight of 1.
+	entries := []DialogueStep{entry("a", 0), entry("b", 3), entry("c", 2)}
+	if total := totalWeight(entries); total != 6 {
+		t.Fatalf("total weight is %v, want 6", total)
+	}
+	for _, tt := range []struct {
+		r    int
+		want string
+	}{
+		{0, "a"},
+		{1, "b"},
+		{3, "b"},
+		{4, "c"},
+		{5, "c"},
+		{6, ""},
+	} {
+		got := ""
+		if steps := pickWeighted(entries, tt.r); steps != nil {
+			got = steps[0].Say[0]
+		}
+		if got != tt.want {
+			t.Errorf("%v: picked %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestCheckStep(t *testing.T) {
+	say := []DialogueStep{{Say: []string{"hi"}}}
+	bad := []DialogueStep{{Say: []string{"hi"}, Do: "crash"}}
+	for _, tt := range []struct {
+		name string
+		step DialogueStep
+		err  string
+	}{
+		{"say", DialogueStep{Say: []string{"hi"}}, ""},
+		{"say with a face", DialogueStep{Speaker: "me", Face: "happy", Say: []string{"hi"}}, ""},
+		{"ask", DialogueStep{Ask: []string{"ok?"}, Yes: say}, ""},
+		{"if", DialogueStep{If: "HasPPE", Then: say, Else: say}, ""},
+		{"roll", DialogueStep{Roll: 20, AtLeast: 10, Then: say}, ""},
+		{"keypad", DialogueStep{Keypad: "0420", Then: say}, ""},
+		{"set", DialogueStep{Set: map[string]bool{"HasPPE": true}}, ""},
+		{"add", DialogueStep{Add: map[string]int{"MarsChecks": 1}}, ""},
+		{"give", DialogueStep{Give: map[string]int{string(CookieItemID): 2}}, ""},
+		{"do", DialogueStep{Do: "crash"}, ""},
+
+		{"nothing", DialogueStep{}, "exactly one"},
+		{"only a speaker", DialogueStep{Speaker: "me"}, "exactly one"},
+		{"two actions", DialogueStep{Say: []string{"hi"}, Do: "crash"}, "exactly one"},
+		{"set and add", DialogueStep{Set: map[string]bool{"HasPPE": true}, Add: map[string]int{"MarsChecks": 1}}, "exactly one"},
+		{"unknown speaker", DialogueStep{Speaker: "ghost", Say: []string{"boo"}}, `no speaker "ghost"`},
+		{"unknown face", DialogueStep{Speaker: "me", Face: "smug", Say: []string{"hi"}}, `doesn't have a "smug" face`},
+		{"face without a portrait", DialogueStep{Face: "happy", Say: []string{"hi"}}, `doesn't have a "happy" face`},
+		{"yes and options", DialogueStep{Ask: []string{"ok?"}, Yes: say, Options: []DialogueStep{{Label: "a"}}}, "both yes/no and options"},
+		{"option without a label", DialogueStep{Ask: []string{"ok?"}, Options: []DialogueStep{{Then: say}}}, "needs a label"},
+		{"unknown flag", DialogueStep{If: "HasPogs", Then: say}, "isn't a save flag"},
+		{"unknown flag in choose", DialogueStep{Choose: []DialogueStep{{If: "HasPogs", Then: say}}}, "isn't a save flag"},
+		{"setting a counter", DialogueStep{Set: map[string]bool{"MarsChecks": true}}, "isn't a save flag"},
+		{"adding to a flag", DialogueStep{Add: map[string]int{"HasPPE": 1}}, "isn't a save counter"},
+		{"negative die", DialogueStep{Roll: -6, Then: say}, "can't roll"},
+		{"letters in a code", DialogueStep{Keypad: "12a4", Then: say}, "can only have digits"},
+		{"spaces in a code", DialogueStep{Keypad: "12 4", Then: say}, "can only have digits"},
+		{"no weights", DialogueStep{Pick: []DialogueStep{}}, "needs at least one entry"},
+		{"negative weight", DialogueStep{Pick: []DialogueStep{{Weight: 2}, {Weight: -1}}}, "can't be negative"},
+		{"unknown item", DialogueStep{Give: map[string]int{"pogs": 1}}, `no item "pogs"`},
+		{"giving none", DialogueStep{Give: map[string]int{string(CookieItemID): 0}}, "can't give 0"},
+		{"unknown action", DialogueStep{Do: "dance"}, `no action "dance"`},
+		{"bad step in then", DialogueStep{If: "HasPPE", Then: bad}, "step 1: a step needs exactly one"},
+		{"bad step in else", DialogueStep{Roll: 6, Else: bad}, "step 1: a step needs exactly one"},
+		{"bad step in a pick", DialogueStep{Pick: []DialogueStep{{Then: bad}}}, "step 1: a step needs exactly one"},
+	} {
+		err := testDialogue().checkStep(&tt.step)
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("%v: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: got error %v, want one saying %q", tt.name, err, tt.err)
+		}
+	}
+
+	// Checking parses the conditions, ready to play.
+	step := DialogueStep{Choose: []DialogueStep{{If: "HasPPE", Then: say}, {Then: say}}}
+	if err := testDialogue().checkStep(&step); err != nil {
+		t.Fatal(err)
+	}
+	if want := []dialogueTerm{{field: "HasPPE"}}; !reflect.DeepEqual(step.Choose[0].cond, want) {
+		t.Errorf("choose condition parsed to %+v, want %+v", step.Choose[0].cond, want)
+	}
+	if step.Choose[1].cond != nil {
+		t.Errorf("choose without an if parsed to %+v", step.Choose[1].cond)
+	}
+}
+
+func TestSkeleScripts(t *testing.T) {
+	data, err := ioutil.ReadFile("assets/dialogue/skele.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts := make(map[string][]DialogueStep)
+	if err := json.Unmarshal(data, &scripts); err != nil {
+		t.Fatal(err)
+	}
+	d := testDialogue()
+	for id, steps := range scripts {
+		if err := d.check(steps); err != nil {
+			t.Errorf("script %q: %v", id, err)
+		}
+	}
+	if _, ok := scripts["intro"]; !ok {
+		t.Error("there's no intro script")
+	}
+}
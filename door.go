@@ -99,7 +99,7 @@ func (s *DoorSystem) Update(dt float32) {
 	for i, entity := range s.entities {
 		if entity.Collides&CollisionGroupDoor != 0 {
 			if entity.IsOpen {
-				if entity.CurrentFrame >= entity.OpenFrame && engo.Input.Button(entity.DoorButton).Down() {
+				if entity.CurrentFrame >= entity.OpenFrame && button(entity.DoorButton).Down() {
 					engo.Mailbox.Dispatch(TeleportPlayerMessage{Pt: entity.TeleportTo})
 				}
 			} else {
@@ -2,13 +2,14 @@ package main
 
 import (
 	"image/color"
+	"log"
 
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 )
 
-// ExitSystem exits the game when you press esc for 3 seconds.
+// ExitSystem saves and exits the game when you press esc for 3 seconds.
 type ExitSystem struct {
 	f      *common.Font
 	entity sprite
@@ -44,7 +45,7 @@ func (e *ExitSystem) New(w *ecs.World) {
 func (e *ExitSystem) Remove(basic ecs.BasicEntity) {}
 
 func (e *ExitSystem) Update(dt float32) {
-	if engo.Input.Button("Exit").Down() {
+	if button("Exit").Down() {
 		e.entity.Hidden = false
 		e.time += dt
 		if e.time < 0.3 {
@@ -63,6 +64,9 @@ func (e *ExitSystem) Update(dt float32) {
 				Font: e.f,
 			}
 		} else {
+			if err := SaveGame(); err != nil {
+				log.Printf("Unable to save the game. Error was: %v", err)
+			}
 			engo.Exit()
 		}
 	} else {
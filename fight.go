@@ -37,6 +37,9 @@ func (s *GhostFightScene) Preload() {
 		"fight/cards.png",
 		"fight/cash.wav",
 		"fight/mimic.png",
+		"fight/ghost.png",
+		"fight/status.png",
+		"faces/party.png",
 		"fight/you.ttf",
 		"fight/boxes.png",
 		"fight/me.ttf",
@@ -56,16 +59,7 @@ func (s *GhostFightScene) Preload() {
 		}
 	}
 
-	engo.Input.RegisterButton("up", engo.KeyW, engo.KeyArrowUp)
-	engo.Input.RegisterButton("down", engo.KeyS, engo.KeyArrowDown)
-	engo.Input.RegisterButton("left", engo.KeyA, engo.KeyArrowLeft)
-	engo.Input.RegisterButton("right", engo.KeyD, engo.KeyArrowRight)
-	engo.Input.RegisterButton("A", engo.KeyJ, engo.KeyZ)
-	engo.Input.RegisterButton("B", engo.KeyK, engo.KeyX)
-	engo.Input.RegisterButton("X", engo.KeyL, engo.KeyC)
-	engo.Input.RegisterButton("Y", engo.KeySemicolon, engo.KeyV)
-	engo.Input.RegisterButton("FullScreen", engo.KeyFour, engo.KeyF4)
-	engo.Input.RegisterButton("Exit", engo.KeyEscape)
+	RegisterControls()
 }
 
 func (s *GhostFightScene) Setup(u engo.Updater) {
@@ -73,6 +67,9 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 
 	rand.Seed(time.Now().UnixNano())
 
+	// The pad goes first so every system reads this frame's buttons.
+	w.AddSystem(&PadSystem{})
+
 	var renderable *common.Renderable
 	var notrenderable *common.NotRenderable
 	w.AddSystemInterface(&common.RenderSystem{}, renderable, notrenderable)
@@ -106,8 +103,11 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 	w.AddSystem(&ExitSystem{})
 
 	var characterable *Characterable
-	w.AddSystemInterface(&BarSystem{}, characterable, nil)
+	var baddieable *Baddieable
+	w.AddSystemInterface(&BarSystem{StatusIconURL: "fight/status.png"}, []interface{}{characterable, baddieable}, nil)
 	w.AddSystemInterface(&CardSelectSystem{}, characterable, nil)
+	w.AddSystemInterface(&TargetSystem{}, []interface{}{characterable, baddieable}, nil)
+	w.AddSystemInterface(&AISystem{}, []interface{}{characterable, baddieable}, nil)
 
 	var phaseable *common.BasicFace
 	w.AddSystemInterface(&PhaseSystem{}, phaseable, nil)
@@ -121,6 +121,8 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 
 	w.AddSystemInterface(&AbilitySelectSystem{fnt: selFont}, characterable, nil)
 	w.AddSystemInterface(&ItemSelectSystem{fnt: selFont}, characterable, nil)
+	w.AddSystem(&BacklogSystem{Fnt: selFont, BackgroundURL: "fight/log.png"})
+	w.AddSystem(&KeypadSystem{Fnt: selFont, BackgroundURL: "fight/log.png"})
 
 	bgm := audio{BasicEntity: ecs.NewBasic()}
 	bgmPlayer, _ := common.LoadedPlayer("fight/bg.ogg")
@@ -135,6 +137,19 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 	logSnd.AudioComponent.Player.SetVolume(0.15)
 	w.AddEntity(&logSnd)
 
+	w.AddSystemInterface(&ResultSystem{
+		Fnt:         selFont,
+		Clip:        logPlayer,
+		ReturnScene: "Skele Scene",
+		Outcome: func(won bool) {
+			if won {
+				CurrentSave.GhostDefeated = true
+			} else {
+				CurrentSave.GhostFightLosses++
+			}
+		},
+	}, []interface{}{characterable, baddieable}, nil)
+
 	youSnd := audio{BasicEntity: ecs.NewBasic()}
 	youPlayer, _ := common.LoadedPlayer("title/log.wav")
 	youSnd.AudioComponent = common.AudioComponent{Player: youPlayer}
@@ -163,6 +178,7 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 	w.AddEntity(&bg)
 
 	cards := common.NewSpritesheetWithBorderFromFile("fight/cards.png", 102, 105, 1, 1)
+	faces := common.NewSpritesheetWithBorderFromFile("faces/party.png", 48, 48, 1, 1)
 	boxes := common.NewSpritesheetWithBorderFromFile("fight/boxes.png", 600, 144, 1, 1)
 	youFnt := &common.Font{
 		Size: 64,
@@ -184,6 +200,7 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 		Int:           40,
 		Font:          youFnt,
 		Clip:          youPlayer,
+		Portrait:      partyPortrait(faces, 0),
 		CardTextScale: engo.Point{X: 0.35, Y: 0.35},
 	}, w)
 	you.MoveCard(engo.Point{X: 320 - you.card.Width/2, Y: 360 - you.card.Height - 10})
@@ -217,6 +234,8 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 			Int:           25,
 			Font:          meFnt,
 			Clip:          mePlayer,
+			Portrait:      partyPortrait(faces, 1),
+			Blip:          Blip{Every: 3},
 			CardTextScale: engo.Point{X: 0.25, Y: 0.25},
 		}, w)
 		you.MoveCard(engo.Point{X: 315 - you.card.Width, Y: 360 - you.card.Height - 10})
@@ -245,6 +264,8 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 			Int:           48,
 			Font:          lenFnt,
 			Clip:          lenPlayer,
+			Portrait:      partyPortrait(faces, 2),
+			Blip:          Blip{Every: 2},
 			CardTextScale: engo.Point{X: 0.35, Y: 0.35},
 		}, w)
 		if CurrentSave.RecruitedLen && CurrentSave.RecruitedMe {
@@ -257,6 +278,45 @@ func (s *GhostFightScene) Setup(u engo.Updater) {
 		}
 	}
 
+	ghostFnt := &common.Font{
+		Size: 64,
+		FG:   color.RGBA{R: 0xb0, G: 0x10, B: 0x1c, A: 0xff},
+		URL:  "fight/log.ttf",
+	}
+	ghostFnt.CreatePreloaded()
+	ghostSS := common.NewSpritesheetWithBorderFromFile("fight/ghost.png", 64, 64, 1, 1)
+	AddBaddie(BaddieInfo{
+		Name:        "Blood Mouthed Ghost",
+		Spritesheet: "fight/ghost.png",
+		CellWidth:   64,
+		CellHeight:  64,
+		Scale:       engo.Point{X: 2, Y: 2},
+		HP:          300,
+		MaxHP:       300,
+		MP:          100,
+		MaxMP:       100,
+		Str:         30,
+		Def:         20,
+		Dex:         40,
+		Int:         35,
+		Font:        ghostFnt,
+		Clip:        logPlayer,
+		Portrait: Portrait{
+			"":      ghostSS.Drawable(0),
+			"angry": ghostSS.Drawable(1),
+			"hurt":  ghostSS.Drawable(2),
+		},
+		Blip: Blip{Pitch: 110, Every: 3},
+		Attacks: []Attack{
+			GhostBiteAttack,
+			GhostEnergyBlastAttack,
+			GhostWailAttack,
+		},
+		MinWait:   4,
+		MaxWait:   8,
+		Targeting: TargetHighestThreat,
+	}, w)
+
 	msgs := []string{
 		"A Blood Mouthed Ghost   Appearerated!",
 	}
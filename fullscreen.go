@@ -5,12 +5,29 @@ import (
 	"github.com/EngoEngine/engo"
 )
 
-type FullScreenSystem struct{}
+// FullScreenSystem goes full screen when FullScreen is pressed. It's paused
+// with the world, so typing a code on the keypad doesn't set it off.
+type FullScreenSystem struct {
+	paused bool
+}
+
+func (f *FullScreenSystem) New(w *ecs.World) {
+	engo.Mailbox.Listen(WorldPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(WorldPauseMessage)
+		if !ok {
+			return
+		}
+		f.paused = msg.Pause
+	})
+}
 
 func (*FullScreenSystem) Remove(basic ecs.BasicEntity) {}
 
 func (f *FullScreenSystem) Update(float32) {
-	if engo.Input.Button("FullScreen").JustPressed() {
+	if f.paused {
+		return
+	}
+	if button("FullScreen").JustPressed() {
 		setFullScreenImpl()
 	}
 }
@@ -0,0 +1,46 @@
+package main
+
+import "time"
+
+// HistoryEntry is a message that was shown in the log.
+type HistoryEntry struct {
+	// Speaker is who said it, or "" if nobody did.
+	Speaker string
+	Msg     string
+	Time    time.Time
+}
+
+// historySize is how many messages History keeps before it starts dropping
+// the oldest.
+const historySize = 200
+
+// History is the last historySize messages shown in the log, kept even after
+// the log's cleared so they can be read again in the backlog.
+type History struct {
+	entries  [historySize]HistoryEntry
+	start, n int
+}
+
+// MessageHistory is the history every scene's log adds to.
+var MessageHistory = &History{}
+
+// Add puts e after the newest message, dropping the oldest if it's full.
+func (h *History) Add(e HistoryEntry) {
+	if h.n < historySize {
+		h.entries[(h.start+h.n)%historySize] = e
+		h.n++
+		return
+	}
+	h.entries[h.start] = e
+	h.start = (h.start + 1) % historySize
+}
+
+// Len is how many messages there are.
+func (h *History) Len() int {
+	return h.n
+}
+
+// At is the i'th message, oldest first.
+func (h *History) At(i int) HistoryEntry {
+	return h.entries[(h.start+i)%historySize]
+}
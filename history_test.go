@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	h := &History{}
+	if h.Len() != 0 {
+		t.Fatalf("new history has %v messages", h.Len())
+	}
+	added := 0
+	for _, tt := range []struct {
+		name string
+		// added is how many messages have been added by now, numbered
+		// from 0.
+		added int
+		n     int
+	}{
+		{"a few", 5, 5},
+		{"one short of full", historySize - 1, historySize - 1},
+		{"full", historySize, historySize},
+		{"one over", historySize + 1, historySize},
+		{"wrapped around", historySize + 50, historySize},
+		{"wrapped around twice", 2*historySize + 3, historySize},
+	} {
+		for ; added < tt.added; added++ {
+			h.Add(HistoryEntry{Msg: strconv.Itoa(added)})
+		}
+		if h.Len() != tt.n {
+			t.Errorf("%v: has %v messages, want %v", tt.name, h.Len(), tt.n)
+		}
+		oldest := tt.added - tt.n
+		for i := 0; i < h.Len(); i++ {
+			if got, want := h.At(i).Msg, strconv.Itoa(oldest+i); got != want {
+				t.Errorf("%v: message %v is %q, want %q", tt.name, i, got, want)
+				break
+			}
+		}
+		if got, want := h.At(h.Len()-1).Msg, strconv.Itoa(tt.added-1); got != want {
+			t.Errorf("%v: newest is %q, want %q", tt.name, got, want)
+		}
+	}
+}
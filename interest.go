@@ -14,6 +14,16 @@ var InterestSystemPauseMessageType = "Interest System Pause Message"
 
 func (InterestSystemPauseMessage) Type() string { return InterestSystemPauseMessageType }
 
+// InterestHoveredMessage asks whether the mouse is over an interest the
+// player can click on.
+type InterestHoveredMessage struct {
+	Hovered bool
+}
+
+var InterestHoveredMessageType = "Interest Hovered Message"
+
+func (*InterestHoveredMessage) Type() string { return InterestHoveredMessageType }
+
 type InterestComponent struct {
 	InterestFunc func()
 }
@@ -27,12 +37,14 @@ type InterestFace interface {
 type InterestAble interface {
 	common.BasicFace
 	common.CollisionFace
+	common.MouseFace
 	InterestFace
 }
 
 type interestEntity struct {
 	*ecs.BasicEntity
 	*common.CollisionComponent
+	*common.MouseComponent
 	*InterestComponent
 }
 
@@ -40,9 +52,24 @@ type InterestSystem struct {
 	entities []interestEntity
 
 	skipNextFrame, paused bool
+	hovered               bool
+
+	// walkingTo is the interest the player was clicked over to, and reached
+	// is set once they get there.
+	walkingTo      uint64
+	reached, check bool
 }
 
 func (s *InterestSystem) New(w *ecs.World) {
+	engo.Mailbox.Listen(InterestHoveredMessageType, func(message engo.Message) {
+		msg, ok := message.(*InterestHoveredMessage)
+		if !ok {
+			return
+		}
+		if !s.paused && s.hovered {
+			msg.Hovered = true
+		}
+	})
 	engo.Mailbox.Listen(InterestSystemPauseMessageType, func(message engo.Message) {
 		msg, ok := message.(InterestSystemPauseMessage)
 		if !ok {
@@ -56,8 +83,8 @@ func (s *InterestSystem) New(w *ecs.World) {
 	})
 }
 
-func (s *InterestSystem) Add(basic *ecs.BasicEntity, collision *common.CollisionComponent, interest *InterestComponent) {
-	s.entities = append(s.entities, interestEntity{basic, collision, interest})
+func (s *InterestSystem) Add(basic *ecs.BasicEntity, collision *common.CollisionComponent, mouse *common.MouseComponent, interest *InterestComponent) {
+	s.entities = append(s.entities, interestEntity{basic, collision, mouse, interest})
 }
 
 func (s *InterestSystem) AddByInterface(i ecs.Identifier) {
@@ -65,7 +92,7 @@ func (s *InterestSystem) AddByInterface(i ecs.Identifier) {
 	if !ok {
 		return
 	}
-	s.Add(o.GetBasicEntity(), o.GetCollisionComponent(), o.GetInterestComponent())
+	s.Add(o.GetBasicEntity(), o.GetCollisionComponent(), o.GetMouseComponent(), o.GetInterestComponent())
 }
 
 func (s *InterestSystem) Remove(basic ecs.BasicEntity) {
@@ -89,19 +116,86 @@ func (s *InterestSystem) Update(dt float32) {
 	if s.paused {
 		return
 	}
-	for _, entity := range s.entities {
-		if entity.Collides&CollisionGroupInterest != 0 {
-			if engo.Input.Button("A").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft) {
-				if entity.InterestFunc != nil {
-					entity.InterestFunc()
+
+	// The collision system needs a frame to catch up with where the player
+	// stopped before checking they made it.
+	if s.reached {
+		if s.check {
+			s.reached, s.check = false, false
+			for _, entity := range s.entities {
+				if entity.ID() == s.walkingTo && entity.Collides&CollisionGroupInterest != 0 {
+					s.trigger(entity)
+					return
 				}
 			}
+		} else {
+			s.check = true
+		}
+	}
+
+	hovered := false
+	onHUD := mouseOnHUD()
+	for _, entity := range s.entities {
+		if entity.Hovered && !onHUD {
+			hovered = true
 		}
+		if entity.Collides&CollisionGroupInterest != 0 && button("A").JustPressed() {
+			s.trigger(entity)
+			return
+		}
+		if entity.Clicked && !onHUD {
+			if entity.Collides&CollisionGroupInterest != 0 {
+				s.trigger(entity)
+				return
+			}
+			s.walkTo(entity)
+		}
+	}
+	s.setHovered(hovered)
+}
+
+func (s *InterestSystem) trigger(entity interestEntity) {
+	s.walkingTo, s.reached, s.check = 0, false, false
+	if entity.InterestFunc != nil {
+		entity.InterestFunc()
+	}
+}
+
+// walkTo walks the player over to where the interest was clicked, so it
+// can be triggered once they're in range.
+func (s *InterestSystem) walkTo(entity interestEntity) {
+	id := entity.ID()
+	s.walkingTo, s.reached, s.check = id, false, false
+	engo.Mailbox.Dispatch(WalkPlayerMessage{
+		Pt: engo.Point{
+			X: entity.MouseX - (playaFeet.Min.X+playaFeet.Max.X)/2,
+			Y: entity.MouseY - (playaFeet.Min.Y+playaFeet.Max.Y)/2,
+		},
+		Arrived: func() {
+			if s.walkingTo == id {
+				s.reached = true
+			}
+		},
+	})
+}
+
+// setHovered shows the hand cursor while the mouse is over an interest.
+func (s *InterestSystem) setHovered(hovered bool) {
+	if hovered == s.hovered {
+		return
+	}
+	s.hovered = hovered
+	if hovered {
+		engo.SetCursor(engo.CursorHand)
+	} else {
+		engo.SetCursor(engo.CursorNone)
 	}
 }
 
 func (s *InterestSystem) pause() {
 	s.paused = true
+	s.walkingTo, s.reached, s.check = 0, false, false
+	s.setHovered(false)
 }
 
 func (s *InterestSystem) unpause() {
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/EngoEngine/engo"
+)
+
+// ItemID is how items are stored in the save. Don't change one once it has
+// shipped or old saves lose the item.
+type ItemID string
+
+const (
+	BandageItemID     ItemID = "bandage"
+	SportsDrinkItemID ItemID = "sports-drink"
+	CookieItemID      ItemID = "cookie"
+)
+
+// ItemOrder is the order items show up in the item menu.
+var ItemOrder = []ItemID{
+	BandageItemID,
+	SportsDrinkItemID,
+	CookieItemID,
+}
+
+// Items is every item in the game, looked up by ID.
+var Items = map[ItemID]Item{
+	BandageItemID: {
+		ID:          BandageItemID,
+		Title:       "Bandage",
+		Shorthand:   "Bndg",
+		Description: "Patch someone up a bit. Stops poison too.",
+		MaxStack:    9,
+		TargetType:  TargetTypeSingleFriend,
+		EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+			for _, tar := range TargetPlayers {
+				amt := ApplyHeal(&tar.StatsComponent, HealAmount(CombatRand, You.Stats(), 20, 10))
+				itemLog(You, You.Name+" bandages up "+tar.Name+" for "+strconv.Itoa(amt)+" HP!")
+				if tar.HasStatus(PoisonStatus.Name) {
+					tar.RemoveStatus(PoisonStatus.Name)
+					itemLog(You, "The poison is all cleaned out.")
+				}
+			}
+		},
+	},
+	SportsDrinkItemID: {
+		ID:          SportsDrinkItemID,
+		Title:       "Sports Drink",
+		Shorthand:   "Drnk",
+		Description: "Electrolytes! Gets some MP back and speeds you up.",
+		MaxStack:    5,
+		TargetType:  TargetTypeSingleFriend,
+		EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+			for _, tar := range TargetPlayers {
+				before := tar.MP
+				tar.MP += 25
+				if tar.MP > tar.MaxMP {
+					tar.MP = tar.MaxMP
+				}
+				itemLog(You, tar.Name+" chugs a sports drink and gets "+strconv.Itoa(int(tar.MP-before))+" MP back!")
+				tar.AddStatus(HasteStatus)
+			}
+		},
+	},
+	CookieItemID: {
+		ID:          CookieItemID,
+		Title:       "Cookie",
+		Shorthand:   "Cook",
+		Description: "Big enough to share with everyone. Sugar rush!",
+		MaxStack:    3,
+		TargetType:  TargetTypeAllFriend,
+		EffectFunc: func(You *Character, TargetPlayers []*Character, TargetBaddies []*Baddie) {
+			itemLog(You, You.Name+" breaks the cookie up for everyone!")
+			for _, tar := range TargetPlayers {
+				if tar.IsKnockedOut {
+					continue
+				}
+				ApplyHeal(&tar.StatsComponent, 10)
+				tar.AddStatus(StrUpStatus)
+			}
+		},
+	},
+}
+
+// ItemByID returns the item definition with the given ID.
+func ItemByID(id ItemID) (Item, bool) {
+	i, ok := Items[id]
+	return i, ok
+}
+
+func itemLog(You *Character, msg string) {
+	engo.Mailbox.Dispatch(You.chat(msg))
+}
+
+// StashCount is how many of an item the party has.
+func (s *SaveData) StashCount(id ItemID) int {
+	return s.Stash[id]
+}
+
+// AddToStash gives the party up to n of an item, stopping at the item's
+// MaxStack, and returns how many were added.
+func (s *SaveData) AddToStash(id ItemID, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if s.Stash == nil {
+		s.Stash = make(map[ItemID]int)
+	}
+	if i, ok := Items[id]; ok && i.MaxStack > 0 && s.Stash[id]+n > i.MaxStack {
+		n = i.MaxStack - s.Stash[id]
+		if n <= 0 {
+			return 0
+		}
+	}
+	s.Stash[id] += n
+	return n
+}
+
+// RemoveFromStash takes n of an item away from the party, and reports false
+// without changing anything if there aren't that many.
+func (s *SaveData) RemoveFromStash(id ItemID, n int) bool {
+	if s.Stash[id] < n {
+		return false
+	}
+	s.Stash[id] -= n
+	if s.Stash[id] == 0 {
+		delete(s.Stash, id)
+	}
+	return true
+}
+
+// RefreshInventory rebuilds the character's Inventory from the party stash.
+func (c *Character) RefreshInventory() {
+	c.Inventory = make([]Item, 0)
+	for _, id := range ItemOrder {
+		n := CurrentSave.StashCount(id)
+		if n <= 0 {
+			continue
+		}
+		i := Items[id]
+		i.Quantity = n
+		c.Inventory = append(c.Inventory, i)
+	}
+}
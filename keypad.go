@@ -0,0 +1,257 @@
+package main
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// KeypadSetMessage sets up the keypad for the next KeypadPhase. Length is
+// how many digits the code has. Once they're entered, Check says whether
+// the code is right and Done is told the code and the answer. Done isn't
+// called if B backs out of the keypad.
+type KeypadSetMessage struct {
+	Length int
+	Check  func(code string) bool
+	Done   func(code string, ok bool)
+}
+
+var KeypadSetMessageType = "Keypad Set Message"
+
+func (KeypadSetMessage) Type() string { return KeypadSetMessageType }
+
+// KeypadOpenMessage opens the keypad. The PhaseSystem sends it on the
+// KeypadPhase, once the log has typed out everything before it.
+type KeypadOpenMessage struct{}
+
+var KeypadOpenMessageType = "Keypad Open Message"
+
+func (KeypadOpenMessage) Type() string { return KeypadOpenMessageType }
+
+// keypadLabels are the keypad's buttons, keypadColumns to a row.
+var keypadLabels = []string{
+	"1", "2", "3",
+	"4", "5", "6",
+	"7", "8", "9",
+	"<", "0", "OK",
+}
+
+const keypadColumns = 3
+
+// digitKeys are the keys for each digit, on the top row and the number pad.
+var digitKeys = [10][2]engo.Key{
+	{engo.KeyZero, engo.KeyNumZero},
+	{engo.KeyOne, engo.KeyNumOne},
+	{engo.KeyTwo, engo.KeyNumTwo},
+	{engo.KeyThree, engo.KeyNumThree},
+	{engo.KeyFour, engo.KeyNumFour},
+	{engo.KeyFive, engo.KeyNumFive},
+	{engo.KeySix, engo.KeyNumSix},
+	{engo.KeySeven, engo.KeyNumSeven},
+	{engo.KeyEight, engo.KeyNumEight},
+	{engo.KeyNine, engo.KeyNumNine},
+}
+
+// KeypadSystem is a number pad for typing in codes. The cursor picks a
+// button and A presses it, or the digits can be typed straight in. B takes
+// the last digit off, or closes the keypad if there aren't any.
+type KeypadSystem struct {
+	Fnt           *common.Font
+	BackgroundURL string
+
+	cursor *CursorSystem
+
+	bg      sprite
+	display sprite
+	keys    []*selection
+
+	set                 KeypadSetMessage
+	code                string
+	open, skipNextFrame bool
+}
+
+func (s *KeypadSystem) New(w *ecs.World) {
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *CursorSystem:
+			s.cursor = sys
+		}
+	}
+
+	s.bg = sprite{BasicEntity: ecs.NewBasic()}
+	s.bg.Drawable, _ = common.LoadedSprite(s.BackgroundURL)
+	s.bg.SetShader(common.HUDShader)
+	s.bg.SetZIndex(10003)
+	s.bg.Position = engo.Point{X: 250, Y: 50}
+	s.bg.Scale = engo.Point{
+		X: 140 / s.bg.Drawable.Width(),
+		Y: 190 / s.bg.Drawable.Height(),
+	}
+	s.bg.Hidden = true
+	w.AddEntity(&s.bg)
+
+	s.display = sprite{BasicEntity: ecs.NewBasic()}
+	s.display.SetShader(common.HUDShader)
+	s.display.SetZIndex(10005)
+	s.display.Position = engo.Point{X: 275, Y: 60}
+	s.display.Scale = engo.Point{X: 0.5, Y: 0.5}
+	s.display.Hidden = true
+	w.AddEntity(&s.display)
+
+	for i, label := range keypadLabels {
+		key := &selection{BasicEntity: ecs.NewBasic()}
+		key.Drawable = common.Text{
+			Text: label,
+			Font: s.Fnt,
+		}
+		key.SetShader(common.HUDShader)
+		key.SetZIndex(10005)
+		key.Position = engo.Point{
+			X: 280 + float32(i%keypadColumns)*35,
+			Y: 105 + float32(i/keypadColumns)*32,
+		}
+		key.Scale = engo.Point{X: 0.4, Y: 0.4}
+		key.Hidden = true
+		w.AddEntity(key)
+		s.keys = append(s.keys, key)
+	}
+
+	engo.Mailbox.Listen(KeypadSetMessageType, func(message engo.Message) {
+		msg, ok := message.(KeypadSetMessage)
+		if !ok {
+			return
+		}
+		s.set = msg
+	})
+	engo.Mailbox.Listen(KeypadOpenMessageType, func(message engo.Message) {
+		_, ok := message.(KeypadOpenMessage)
+		if !ok {
+			return
+		}
+		s.show()
+	})
+	engo.Mailbox.Listen(HUDClickMessageType, func(message engo.Message) {
+		msg, ok := message.(*HUDClickMessage)
+		if !ok {
+			return
+		}
+		if s.bg.shows(msg.Pt) {
+			msg.Hit = true
+		}
+	})
+}
+
+func (s *KeypadSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *KeypadSystem) Update(dt float32) {
+	if s.skipNextFrame {
+		s.skipNextFrame = false
+		return
+	}
+	if !s.open {
+		return
+	}
+	for d, keys := range digitKeys {
+		for _, k := range keys {
+			if engo.Input.Button(keyButton(k)).JustPressed() {
+				s.press(strconv.Itoa(d))
+				return
+			}
+		}
+	}
+	if engo.Input.Button(keyButton(engo.KeyBackspace)).JustPressed() {
+		s.press("<")
+	} else if engo.Input.Button(keyButton(engo.KeyEnter)).JustPressed() {
+		s.press("OK")
+	} else if button("A").JustPressed() {
+		for i, key := range s.keys {
+			if key.Selected {
+				s.press(keypadLabels[i])
+				return
+			}
+		}
+	} else if button("B").JustPressed() {
+		if s.code == "" {
+			s.close()
+		} else {
+			s.press("<")
+		}
+	}
+}
+
+// press does what the button with label does.
+func (s *KeypadSystem) press(label string) {
+	switch label {
+	case "<":
+		if s.code != "" {
+			s.code = s.code[:len(s.code)-1]
+		}
+	case "OK":
+		if len(s.code) < s.set.Length {
+			return
+		}
+		code := s.code
+		ok := s.set.Check == nil || s.set.Check(code)
+		s.close()
+		if s.set.Done != nil {
+			s.set.Done(code, ok)
+		}
+		return
+	default:
+		if len(s.code) >= s.set.Length {
+			return
+		}
+		s.code += label
+		if len(s.code) == s.set.Length {
+			engo.Mailbox.Dispatch(CursorSetMessage{ID: s.keys[len(s.keys)-1].BasicEntity})
+		}
+	}
+	s.refresh()
+}
+
+// refresh shows the digits typed so far, with a blank for each one left.
+func (s *KeypadSystem) refresh() {
+	digits := strings.Split(s.code, "")
+	for len(digits) < s.set.Length {
+		digits = append(digits, "_")
+	}
+	s.display.Drawable = common.Text{
+		Text: strings.Join(digits, " "),
+		Font: s.Fnt,
+	}
+	s.keys[len(s.keys)-1].Color = color.White
+	if len(s.code) < s.set.Length {
+		s.keys[len(s.keys)-1].Color = menuDisabled
+	}
+}
+
+func (s *KeypadSystem) show() {
+	s.open = true
+	s.skipNextFrame = true
+	s.code = ""
+	s.bg.Hidden = false
+	s.display.Hidden = false
+	for i, key := range s.keys {
+		key.Hidden = false
+		key.Selected = i == 0
+		s.cursor.AddByInterface(key)
+	}
+	engo.Mailbox.Dispatch(CursorJumpSetMessage{Jump: keypadColumns})
+	s.refresh()
+}
+
+func (s *KeypadSystem) close() {
+	s.open = false
+	s.bg.Hidden = true
+	s.display.Hidden = true
+	for _, key := range s.keys {
+		key.Hidden = true
+		s.cursor.Remove(key.BasicEntity)
+	}
+	engo.Mailbox.Dispatch(CursorJumpSetMessage{Jump: 1})
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+}
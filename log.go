@@ -2,7 +2,9 @@ package main
 
 import (
 	"image/color"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
@@ -13,6 +15,18 @@ type CombatLogMessage struct {
 	Msg  string
 	Fnt  *common.Font
 	Clip *common.Player
+	// Speaker is who's talking, for the backlog. Leave it empty for
+	// narration.
+	Speaker string
+	// LetterDelay is how long each letter takes to type, or 0 for the
+	// CombatLogSystem's LetterDelay.
+	LetterDelay float32
+	// Portrait is shown beside the log with Speaker's name plate while the
+	// message is up. Blip is how it sounds as it's typed, and Dot replaces
+	// the log's dot beside it.
+	Portrait common.Drawable
+	Blip     Blip
+	Dot      common.Drawable
 }
 
 var CombatLogMessageType = "CombatLogMessage"
@@ -47,21 +61,70 @@ var CombatLogClearMessageType = "Combat Log Clear Message"
 
 func (m CombatLogClearMessage) Type() string { return CombatLogClearMessageType }
 
+// logRows is how many lines the log shows, and logRuns how many colors each
+// line can have. Anything past the last color is drawn in it.
+const (
+	logRows = 3
+	logRuns = 6
+)
+
+// logTextX is where the log's lines start, and logMargin how far from the
+// edge of the box they have to stop.
+const (
+	logTextX  = 99
+	logMargin = 12
+)
+
+var logTextScale = engo.Point{X: 0.35, Y: 0.35}
+
+// portraitHeight is how tall portraits are drawn, and plateHeight and
+// platePadding the size of the name plate.
+const (
+	portraitHeight = 64
+	plateHeight    = 20
+	platePadding   = 6
+)
+
+// logLine is one line of the log box, typed out a letter at a time.
+type logLine struct {
+	chars []markupChar
+	fnt   *common.Font
+	shown int
+	// dot marks the first line of a message.
+	dot bool
+
+	dotSpr sprite
+	runs   [logRuns]sprite
+}
+
 type CombatLogSystem struct {
-	lock                                      sync.RWMutex
-	log                                       []CombatLogMessage
-	idx, charAt                               int
-	done, moved                               bool
-	elapsed                                   float32
-	BackgroundURL, FontURL, DotURL            string
-	font                                      *common.Font
-	LineDelay, LetterDelay                    float32
-	bg, dot1, dot2, dot3, line1, line2, line3 sprite
-	paused, skipNextFrame                     bool
-	dot1Shown, dot2Shown, dot3Shown           bool
+	lock                           sync.RWMutex
+	log                            []CombatLogMessage
+	idx                            int
+	done, moved                    bool
+	elapsed, wait                  float32
+	BackgroundURL, FontURL, DotURL string
+	font                           *common.Font
+	LineDelay, LetterDelay         float32
+	bg                             sprite
+	rows                           [logRows]*logLine
+	pending                        [][]markupChar
+	paused, skipNextFrame          bool
+
+	world  *ecs.World
+	dotTex common.Drawable
+
+	// The speaker's portrait and name plate, for messages that have them.
+	portrait, plate, name sprite
+	hasPortrait, hasPlate bool
+	blips                 map[float32]*common.Player
+	letters               int
 }
 
 func (s *CombatLogSystem) New(w *ecs.World) {
+	s.world = w
+	s.blips = make(map[float32]*common.Player)
+
 	//bg
 	s.bg = sprite{BasicEntity: ecs.NewBasic()}
 	s.bg.Drawable, _ = common.LoadedSprite(s.BackgroundURL)
@@ -72,71 +135,60 @@ func (s *CombatLogSystem) New(w *ecs.World) {
 	s.bg.SetCenter(engo.Point{X: 320, Y: s.bg.Height / 2})
 	w.AddEntity(&s.bg)
 
-	dotTex, _ := common.LoadedSprite(s.DotURL)
-	//dot1
-	s.dot1 = sprite{BasicEntity: ecs.NewBasic()}
-	s.dot1.Drawable = dotTex
-	s.dot1.SetShader(common.HUDShader)
-	s.dot1.SetZIndex(10003)
-	s.dot1.SetCenter(engo.Point{X: 84, Y: 55})
-	s.dot1.Hidden = true
-	w.AddEntity(&s.dot1)
-	//dot2
-	s.dot2 = sprite{BasicEntity: ecs.NewBasic()}
-	s.dot2.Drawable = dotTex
-	s.dot2.SetShader(common.HUDShader)
-	s.dot2.SetZIndex(10003)
-	s.dot2.SetCenter(engo.Point{X: 84, Y: 35})
-	s.dot2.Hidden = true
-	w.AddEntity(&s.dot2)
-	//dot3
-	s.dot3 = sprite{BasicEntity: ecs.NewBasic()}
-	s.dot3.Drawable = dotTex
-	s.dot3.SetShader(common.HUDShader)
-	s.dot3.SetZIndex(10003)
-	s.dot3.SetCenter(engo.Point{X: 84, Y: 15})
-	s.dot3.Hidden = true
-	w.AddEntity(&s.dot3)
-
 	s.font = &common.Font{
 		Size: 64,
 		FG:   color.Black,
 		URL:  s.FontURL,
 	}
 	s.font.CreatePreloaded()
-	//line1
-	s.line1 = sprite{BasicEntity: ecs.NewBasic()}
-	s.line1.Drawable = common.Text{
-		Font: s.font,
-		Text: "",
-	}
-	s.line1.SetShader(common.TextHUDShader)
-	s.line1.Scale = engo.Point{X: 0.35, Y: 0.35}
-	s.line1.SetZIndex(10003)
-	s.line1.Position = engo.Point{X: 99, Y: 48}
-	w.AddEntity(&s.line1)
-	//line2
-	s.line2 = sprite{BasicEntity: ecs.NewBasic()}
-	s.line2.Drawable = common.Text{
-		Font: s.font,
-		Text: "",
+
+	dotTex, _ := common.LoadedSprite(s.DotURL)
+	s.dotTex = dotTex
+	for i := range s.rows {
+		row := &logLine{fnt: s.font}
+		row.dotSpr = sprite{BasicEntity: ecs.NewBasic()}
+		row.dotSpr.Drawable = dotTex
+		row.dotSpr.SetShader(common.HUDShader)
+		row.dotSpr.SetZIndex(10003)
+		row.dotSpr.Hidden = true
+		w.AddEntity(&row.dotSpr)
+		for j := range row.runs {
+			run := &row.runs[j]
+			run.BasicEntity = ecs.NewBasic()
+			run.Drawable = common.Text{
+				Font: s.font,
+				Text: "",
+			}
+			run.SetShader(common.TextHUDShader)
+			run.Scale = logTextScale
+			run.SetZIndex(10003)
+			w.AddEntity(run)
+		}
+		s.rows[i] = row
 	}
-	s.line2.SetShader(common.TextHUDShader)
-	s.line2.Scale = engo.Point{X: 0.35, Y: 0.35}
-	s.line2.SetZIndex(10003)
-	s.line2.Position = engo.Point{X: 99, Y: 28}
-	w.AddEntity(&s.line2)
-	//line3
-	s.line3 = sprite{BasicEntity: ecs.NewBasic()}
-	s.line3.Drawable = common.Text{
+	s.place()
+
+	s.plate = sprite{BasicEntity: ecs.NewBasic()}
+	s.plate.Drawable = s.bg.Drawable
+	s.plate.SetShader(common.HUDShader)
+	s.plate.SetZIndex(10002)
+	s.plate.Hidden = true
+	w.AddEntity(&s.plate)
+	s.name = sprite{BasicEntity: ecs.NewBasic()}
+	s.name.Drawable = common.Text{
 		Font: s.font,
 		Text: "",
 	}
-	s.line3.SetShader(common.TextHUDShader)
-	s.line3.Scale = engo.Point{X: 0.35, Y: 0.35}
-	s.line3.SetZIndex(10003)
-	s.line3.Position = engo.Point{X: 99, Y: 8}
-	w.AddEntity(&s.line3)
+	s.name.SetShader(common.TextHUDShader)
+	s.name.Scale = logTextScale
+	s.name.SetZIndex(10003)
+	s.name.Hidden = true
+	w.AddEntity(&s.name)
+	s.portrait = sprite{BasicEntity: ecs.NewBasic()}
+	s.portrait.SetShader(common.HUDShader)
+	s.portrait.SetZIndex(10003)
+	s.portrait.Hidden = true
+	w.AddEntity(&s.portrait)
 
 	engo.Mailbox.Listen(CombatLogMessageType, func(message engo.Message) {
 		msg, ok := message.(CombatLogMessage)
@@ -146,6 +198,11 @@ func (s *CombatLogSystem) New(w *ecs.World) {
 		s.lock.Lock()
 		defer s.lock.Unlock()
 		s.log = append(s.log, msg)
+		MessageHistory.Add(HistoryEntry{
+			Speaker: msg.Speaker,
+			Msg:     plainText(parseMarkup(msg.Msg)),
+			Time:    time.Now(),
+		})
 	})
 
 	engo.Mailbox.Listen(CombatLogDoneMessageType, func(message engo.Message) {
@@ -177,6 +234,16 @@ func (s *CombatLogSystem) New(w *ecs.World) {
 		}
 		s.clear()
 	})
+
+	engo.Mailbox.Listen(HUDClickMessageType, func(message engo.Message) {
+		msg, ok := message.(*HUDClickMessage)
+		if !ok {
+			return
+		}
+		if s.bg.shows(msg.Pt) || s.plate.shows(msg.Pt) || s.portrait.shows(msg.Pt) {
+			msg.Hit = true
+		}
+	})
 }
 
 func (s *CombatLogSystem) Remove(basic ecs.BasicEntity) {}
@@ -196,88 +263,229 @@ func (s *CombatLogSystem) Update(dt float32) {
 			s.moved = false
 			s.done = false
 		}
-	} else {
-		if !s.moved && len(s.log) > 0 {
-			if s.elapsed < s.LineDelay {
-				return
-			}
-			s.elapsed = 0
-			s.dot1.Hidden = false
-			s.dot1Shown = true
-			s.line3.Drawable = s.line2.Drawable
-			s.line2.Drawable = s.line1.Drawable
-			txt := s.line1.Drawable.(common.Text)
-			txt.Font = s.log[s.idx].Fnt
-			txt.Text = ""
-			s.line1.Drawable = txt
-			s.moved = true
-			txt2 := s.line2.Drawable.(common.Text)
-			txt3 := s.line3.Drawable.(common.Text)
-			if txt2.Text != "" {
-				s.dot2.Hidden = false
-				s.dot2Shown = true
-			}
-			if txt3.Text != "" {
-				s.dot3.Hidden = false
-				s.dot3Shown = true
-			}
+		return
+	}
+	if len(s.log) == 0 {
+		return
+	}
+	msg := s.log[s.idx]
+	if !s.moved {
+		if s.elapsed < s.LineDelay {
+			return
 		}
-		if len(s.log) > 0 && s.elapsed > s.LetterDelay {
-			s.charAt++
-			txt := s.line1.Drawable.(common.Text)
-			txt.Text = s.log[s.idx].Msg[:s.charAt]
-			s.line1.Drawable = txt
-			s.elapsed = 0
-			if !s.log[s.idx].Clip.IsPlaying() {
-				s.log[s.idx].Clip.Rewind()
-				s.log[s.idx].Clip.Play()
-			}
+		s.elapsed = 0
+		s.wait = 0
+		s.pending = wrapMarkup(parseMarkup(msg.Msg), s.lineWidth(), func(text string) float32 {
+			return textWidth(msg.Fnt, text)
+		})
+		s.newLine(msg.Fnt, true)
+		s.rows[0].dotSpr.Drawable = s.dotTex
+		if msg.Dot != nil {
+			s.rows[0].dotSpr.Drawable = msg.Dot
 		}
-		if engo.Input.Button("Y").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonRight) {
-			txt := s.line1.Drawable.(common.Text)
-			txt.Text = s.log[s.idx].Msg
-			s.line1.Drawable = txt
-			s.charAt = 0
-			s.elapsed = 0
-			s.done = true
+		s.showSpeaker(msg)
+		s.letters = 0
+		s.moved = true
+	}
+	delay := s.wait
+	if delay == 0 {
+		delay = msg.LetterDelay
+	}
+	if delay == 0 {
+		delay = s.LetterDelay
+	}
+	if s.elapsed > delay {
+		s.elapsed = 0
+		s.wait = s.typeNext()
+		if s.wait == 0 {
+			s.blip(msg)
 		}
-		if len(s.log) > 0 && s.charAt >= len(s.log[s.idx].Msg) {
-			s.charAt = 0
-			s.elapsed = 0
-			s.done = true
+	}
+	if button("Y").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonRight) {
+		s.typeAll()
+	}
+	if s.typed() {
+		s.elapsed = 0
+		s.done = true
+	}
+}
+
+// showSpeaker shows the name plate and portrait for msg, if it has them.
+// The plate goes under the log box and the portrait under that.
+func (s *CombatLogSystem) showSpeaker(msg CombatLogMessage) {
+	s.hasPlate = msg.Speaker != ""
+	s.hasPortrait = msg.Portrait != nil
+	y := s.bg.Position.Y + s.bg.Height + 2
+	if s.hasPlate {
+		w := textWidth(msg.Fnt, msg.Speaker) + 2*platePadding
+		s.plate.Position = engo.Point{X: s.bg.Position.X, Y: y}
+		s.plate.Scale = engo.Point{X: w / s.bg.Width, Y: plateHeight / s.bg.Height}
+		s.name.Drawable = common.Text{
+			Font: msg.Fnt,
+			Text: msg.Speaker,
 		}
+		s.name.Position = engo.Point{X: s.bg.Position.X + platePadding, Y: y + 2}
+		y += plateHeight + 2
+	}
+	if s.hasPortrait {
+		scale := portraitHeight / msg.Portrait.Height()
+		s.portrait.Drawable = msg.Portrait
+		s.portrait.Scale = engo.Point{X: scale, Y: scale}
+		s.portrait.Position = engo.Point{X: s.bg.Position.X, Y: y}
 	}
+	s.showSpeakerSprites(!s.paused)
 }
 
-func (s *CombatLogSystem) clear() {
-	s.line1.Drawable = common.Text{
-		Font: s.font,
-		Text: "",
+func (s *CombatLogSystem) showSpeakerSprites(visible bool) {
+	s.plate.Hidden = !visible || !s.hasPlate
+	s.name.Hidden = !visible || !s.hasPlate
+	s.portrait.Hidden = !visible || !s.hasPortrait
+}
+
+// blip plays msg's sound for a letter. With a pitch it's a made up blip
+// instead of msg's Clip, and with Every it only plays every so many letters.
+func (s *CombatLogSystem) blip(msg CombatLogMessage) {
+	clip := msg.Clip
+	if msg.Blip.Pitch > 0 {
+		if p := s.blipPlayer(msg.Blip.Pitch); p != nil {
+			clip = p
+		}
 	}
-	s.dot1Shown = false
-	s.line2.Drawable = common.Text{
-		Font: s.font,
-		Text: "",
+	if clip == nil {
+		return
 	}
-	s.dot2Shown = false
-	s.line3.Drawable = common.Text{
-		Font: s.font,
-		Text: "",
+	s.letters++
+	if msg.Blip.Every > 0 {
+		if (s.letters-1)%msg.Blip.Every != 0 {
+			return
+		}
+	} else if clip.IsPlaying() {
+		return
+	}
+	clip.Rewind()
+	clip.Play()
+}
+
+// blipPlayer is the player for the blip at pitch, or nil if it can't be
+// made.
+func (s *CombatLogSystem) blipPlayer(pitch float32) *common.Player {
+	if p, ok := s.blips[pitch]; ok {
+		return p
+	}
+	url, err := blipURL(pitch)
+	if err != nil {
+		log.Printf("Unable to make the %v Hz blip. Error was: %v", pitch, err)
+		s.blips[pitch] = nil
+		return nil
+	}
+	p, err := common.LoadedPlayer(url)
+	if err != nil {
+		log.Printf("Unable to load the %v Hz blip. Error was: %v", pitch, err)
+		s.blips[pitch] = nil
+		return nil
+	}
+	p.SetVolume(0.15)
+	snd := audio{BasicEntity: ecs.NewBasic()}
+	snd.AudioComponent = common.AudioComponent{Player: p}
+	s.world.AddEntity(&snd)
+	s.blips[pitch] = p
+	return p
+}
+
+// lineWidth is how wide a line can be before it wraps.
+func (s *CombatLogSystem) lineWidth() float32 {
+	return s.bg.Position.X + s.bg.Width - logTextX - logMargin
+}
+
+// newLine scrolls the log up a line and starts the next pending one in fnt.
+func (s *CombatLogSystem) newLine(fnt *common.Font, dot bool) {
+	row := s.rows[logRows-1]
+	copy(s.rows[1:], s.rows[:logRows-1])
+	s.rows[0] = row
+	row.fnt = fnt
+	row.dot = dot
+	row.chars = nil
+	row.shown = 0
+	if len(s.pending) > 0 {
+		row.chars = s.pending[0]
+		s.pending = s.pending[1:]
+	}
+	s.place()
+}
+
+// typeNext types the next letter of the message, moving onto the next line if
+// this one's done. It returns how long the letter says to wait, if it's a
+// wait.
+func (s *CombatLogSystem) typeNext() float32 {
+	row := s.rows[0]
+	for row.shown == len(row.chars) && len(s.pending) > 0 {
+		s.newLine(row.fnt, false)
+		row = s.rows[0]
+	}
+	if row.shown == len(row.chars) {
+		return 0
+	}
+	c := row.chars[row.shown]
+	row.shown++
+	row.render()
+	return c.wait
+}
+
+// typeAll shows the rest of the message at once.
+func (s *CombatLogSystem) typeAll() {
+	for {
+		row := s.rows[0]
+		row.shown = len(row.chars)
+		row.render()
+		if len(s.pending) == 0 {
+			break
+		}
+		s.newLine(row.fnt, false)
+	}
+	s.wait = 0
+}
+
+// typed reports whether the whole message is showing.
+func (s *CombatLogSystem) typed() bool {
+	return s.moved && s.rows[0].shown == len(s.rows[0].chars) && len(s.pending) == 0
+}
+
+// place puts the rows where they go, newest at the bottom.
+func (s *CombatLogSystem) place() {
+	for i, row := range s.rows {
+		y := float32(48 - 20*i)
+		row.dotSpr.SetCenter(engo.Point{X: 84, Y: y + 7})
+		for j := range row.runs {
+			row.runs[j].Position.Y = y
+		}
+		row.render()
+		row.show(!s.paused)
+	}
+}
+
+func (s *CombatLogSystem) clear() {
+	for _, row := range s.rows {
+		row.fnt = s.font
+		row.chars = nil
+		row.shown = 0
+		row.dot = false
 	}
-	s.dot3Shown = false
+	s.place()
+	s.hasPlate = false
+	s.hasPortrait = false
+	s.showSpeakerSprites(false)
+	s.pending = nil
+	s.wait = 0
 	s.idx = -1
-	s.charAt = 0
 	s.log = make([]CombatLogMessage, 0)
 }
 
 func (s *CombatLogSystem) pause() {
 	s.bg.Hidden = true
-	s.line1.Hidden = true
-	s.line2.Hidden = true
-	s.line3.Hidden = true
-	s.dot1.Hidden = true
-	s.dot2.Hidden = true
-	s.dot3.Hidden = true
+	for _, row := range s.rows {
+		row.show(false)
+	}
+	s.showSpeakerSprites(false)
 	s.paused = true
 }
 
@@ -285,16 +493,82 @@ func (s *CombatLogSystem) unpause() {
 	s.paused = false
 	s.skipNextFrame = true
 	s.bg.Hidden = false
-	s.line1.Hidden = false
-	s.line2.Hidden = false
-	s.line3.Hidden = false
-	if s.dot1Shown {
-		s.dot1.Hidden = false
+	for _, row := range s.rows {
+		row.show(true)
+	}
+	s.showSpeakerSprites(true)
+}
+
+// render draws the letters typed so far, a run for each color.
+func (l *logLine) render() {
+	var (
+		texts  [logRuns]string
+		colors [logRuns]color.Color
+		run    = -1
+	)
+	for _, c := range l.chars[:l.shown] {
+		if c.wait > 0 {
+			continue
+		}
+		if run < 0 || run < logRuns-1 && c.color != colors[run] {
+			run++
+			colors[run] = c.color
+		}
+		texts[run] += string(c.ch)
+	}
+	x := float32(logTextX)
+	for i := range l.runs {
+		l.runs[i].Drawable = common.Text{
+			Font: tintedFont(l.fnt, colors[i]),
+			Text: texts[i],
+		}
+		l.runs[i].Position.X = x
+		x += textWidth(l.fnt, texts[i])
+	}
+}
+
+func (l *logLine) show(visible bool) {
+	for i := range l.runs {
+		l.runs[i].Hidden = !visible
+	}
+	l.dotSpr.Hidden = !visible || !l.dot
+}
+
+// textWidth is how wide text is in fnt at the log's scale.
+func textWidth(fnt *common.Font, text string) float32 {
+	if fnt == nil || text == "" {
+		return 0
+	}
+	w, _, _ := fnt.TextDimensions(text)
+	return float32(w) * logTextScale.X
+}
+
+type tintKey struct {
+	fnt *common.Font
+	c   color.RGBA
+}
+
+// tintedFonts are fonts made by tintedFont, so each is only made once.
+var tintedFonts = map[tintKey]*common.Font{}
+
+// tintedFont is fnt drawn in c instead of its own color. A nil c is just fnt.
+func tintedFont(fnt *common.Font, c color.Color) *common.Font {
+	if fnt == nil || c == nil {
+		return fnt
+	}
+	key := tintKey{fnt: fnt, c: color.RGBAModel.Convert(c).(color.RGBA)}
+	if f, ok := tintedFonts[key]; ok {
+		return f
 	}
-	if s.dot2Shown {
-		s.dot2.Hidden = false
+	f := &common.Font{
+		URL:  fnt.URL,
+		Size: fnt.Size,
+		BG:   fnt.BG,
+		FG:   c,
 	}
-	if s.dot3Shown {
-		s.dot3.Hidden = false
+	if err := f.CreatePreloaded(); err != nil {
+		return fnt
 	}
+	tintedFonts[key] = f
+	return f
 }
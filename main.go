@@ -1,6 +1,10 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 )
@@ -25,18 +29,31 @@ type SaveData struct {
 	RecruitedLen          bool
 	RecruitedMe           bool
 	PlayerLocation        engo.Point
-	DrinkCount            int
-	CookieCount           int
-	BandageCount          int
+	Stash                 map[ItemID]int
 	HasMedKit             bool
 	HasSalt               bool
+	GhostDefeated         bool
+	GhostFightLosses      int
+	SafePinMisses         int
+	SawIntro              bool
 }
 
-var CurrentSave = &SaveData{
-	PlayerLocation: engo.Point{X: 300, Y: 125},
-}
+var CurrentSave = NewSaveData()
 
 func main() {
+	flag.IntVar(&CurrentSlot, "slot", 1, "save slot to load and save to")
+	checkRooms := flag.Bool("check-rooms", false, "check the room maps and where their doors go, then exit")
+	flag.Parse()
+	if *checkRooms {
+		os.Exit(runRoomCheck())
+	}
+	if save, err := LoadGame(CurrentSlot); err != nil {
+		log.Printf("Unable to load save slot %v, starting a new game. Error was: %v", CurrentSlot, err)
+	} else {
+		CurrentSave = save
+	}
+	LoadBindings()
+
 	common.AddShader(fightShader)
 	skeleScene := &SkeleScene{}
 	engo.RegisterScene(skeleScene)
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Messages in the log can use markup:
+//
+//	[red]SPACE KEY[/]   draws SPACE KEY in red. Colors can be named or
+//	                    written like [#ff8800].
+//	[em]this[/]         draws this in the emphasis color.
+//	[wait=0.5]          waits half a second before typing what's next.
+//	[[                  is a plain [.
+//
+// Anything else in brackets is shown as it is.
+
+// markupColors are the colors markup can use by name.
+var markupColors = map[string]color.Color{
+	"red":    color.RGBA{R: 0xe0, G: 0x30, B: 0x30, A: 0xff},
+	"green":  color.RGBA{R: 0x40, G: 0xc0, B: 0x40, A: 0xff},
+	"blue":   color.RGBA{R: 0x40, G: 0x70, B: 0xe0, A: 0xff},
+	"yellow": color.RGBA{R: 0xf0, G: 0xd0, B: 0x30, A: 0xff},
+	"orange": color.RGBA{R: 0xf0, G: 0x90, B: 0x20, A: 0xff},
+	"purple": color.RGBA{R: 0xa0, G: 0x50, B: 0xd0, A: 0xff},
+	"white":  color.White,
+	"black":  color.Black,
+	"gray":   color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"grey":   color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"em":     color.RGBA{R: 0xff, G: 0xc0, B: 0x40, A: 0xff},
+}
+
+// markupChar is one letter of a message with its markup worked out. A wait
+// is a markupChar of its own with no letter.
+type markupChar struct {
+	ch rune
+	// color is nil for the message's own color.
+	color color.Color
+	wait  float32
+}
+
+// parseMarkup takes the markup out of msg.
+func parseMarkup(msg string) []markupChar {
+	var (
+		out []markupChar
+		c   color.Color
+	)
+	for len(msg) > 0 {
+		if strings.HasPrefix(msg, "[[") {
+			out = append(out, markupChar{ch: '[', color: c})
+			msg = msg[2:]
+			continue
+		}
+		if msg[0] == '[' {
+			if end := strings.IndexByte(msg, ']'); end > 0 {
+				if tagColor, wait, ok := parseTag(msg[1:end]); ok {
+					if wait > 0 {
+						out = append(out, markupChar{wait: wait})
+					} else {
+						c = tagColor
+					}
+					msg = msg[end+1:]
+					continue
+				}
+			}
+		}
+		r, size := utf8.DecodeRuneInString(msg)
+		out = append(out, markupChar{ch: r, color: c})
+		msg = msg[size:]
+	}
+	return out
+}
+
+// parseTag works out what's inside a pair of brackets. "/" goes back to the
+// message's own color, which is a nil color with no wait.
+func parseTag(tag string) (color.Color, float32, bool) {
+	tag = strings.TrimSpace(strings.ToLower(tag))
+	if tag == "/" {
+		return nil, 0, true
+	}
+	if strings.HasPrefix(tag, "wait=") {
+		secs, err := strconv.ParseFloat(tag[len("wait="):], 32)
+		if err != nil || secs <= 0 {
+			return nil, 0, false
+		}
+		return nil, float32(secs), true
+	}
+	if c, ok := markupColors[tag]; ok {
+		return c, 0, true
+	}
+	if len(tag) == 7 && tag[0] == '#' {
+		rgb, err := strconv.ParseUint(tag[1:], 16, 32)
+		if err != nil {
+			return nil, 0, false
+		}
+		return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, 0, true
+	}
+	return nil, 0, false
+}
+
+// plainText is the letters in chars, without any markup.
+func plainText(chars []markupChar) string {
+	var b strings.Builder
+	for _, c := range chars {
+		if c.wait == 0 {
+			b.WriteRune(c.ch)
+		}
+	}
+	return b.String()
+}
+
+// wrapMarkup splits chars into lines no wider than width, breaking at spaces
+// and at any "\n". measure is how wide a piece of plain text is. A word too
+// long for a line on its own is broken wherever it has to be. The spaces
+// lines are broken at are dropped.
+func wrapMarkup(chars []markupChar, width float32, measure func(string) float32) [][]markupChar {
+	var lines [][]markupChar
+	for {
+		end, next := lineEnd(chars, width, measure)
+		lines = append(lines, chars[:end])
+		chars = chars[next:]
+		if len(chars) == 0 {
+			return lines
+		}
+	}
+}
+
+// wrapText is text with "\n"s put in so none of its lines are wider than
+// width. It doesn't have any markup.
+func wrapText(text string, width float32, measure func(string) float32) string {
+	var chars []markupChar
+	for _, r := range text {
+		chars = append(chars, markupChar{ch: r})
+	}
+	var lines []string
+	for _, line := range wrapMarkup(chars, width, measure) {
+		lines = append(lines, plainText(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineEnd is where the first line of chars ends, and where the line after it
+// starts.
+func lineEnd(chars []markupChar, width float32, measure func(string) float32) (int, int) {
+	lastSpace := -1
+	for i, c := range chars {
+		if c.wait > 0 {
+			continue
+		}
+		if c.ch == '\n' {
+			return i, i + 1
+		}
+		if c.ch == ' ' {
+			lastSpace = i
+			continue
+		}
+		if measure(plainText(chars[:i+1])) <= width {
+			continue
+		}
+		if lastSpace > 0 {
+			return lastSpace, lastSpace + 1
+		}
+		if i == 0 {
+			return 1, 1
+		}
+		return i, i
+	}
+	return len(chars), len(chars)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// monospace measures every letter as 1 wide.
+func monospace(s string) float32 {
+	return float32(utf8.RuneCountInString(s))
+}
+
+// letters is chars as a string, with a | for each wait.
+func letters(chars []markupChar) string {
+	var s []rune
+	for _, c := range chars {
+		if c.wait > 0 {
+			s = append(s, '|')
+		} else {
+			s = append(s, c.ch)
+		}
+	}
+	return string(s)
+}
+
+func TestParseMarkup(t *testing.T) {
+	red := markupColors["red"]
+	blue := markupColors["blue"]
+	orange := color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}
+	for _, tt := range []struct {
+		name, msg, want string
+		colors          []color.Color
+	}{
+		{
+			name: "plain",
+			msg:  "hi",
+			want: "hi",
+		},
+		{
+			name:   "color",
+			msg:    "a[red]b[/]c",
+			want:   "abc",
+			colors: []color.Color{nil, red, nil},
+		},
+		{
+			// Tags don't nest, a new color takes over and [/] goes all the
+			// way back.
+			name:   "nested",
+			msg:    "[red]a[blue]b[/]c",
+			want:   "abc",
+			colors: []color.Color{red, blue, nil},
+		},
+		{
+			name:   "upper case",
+			msg:    "[RED]a",
+			want:   "a",
+			colors: []color.Color{red},
+		},
+		{
+			name:   "hex",
+			msg:    "[#ff8800]a[/]",
+			want:   "a",
+			colors: []color.Color{orange},
+		},
+		{
+			name: "bad hex",
+			msg:  "[#ff88zz]a",
+			want: "[#ff88zz]a",
+		},
+		{
+			name: "short hex",
+			msg:  "[#f80]a",
+			want: "[#f80]a",
+		},
+		{
+			name: "unknown tag",
+			msg:  "[mauve]a",
+			want: "[mauve]a",
+		},
+		{
+			name: "empty tag",
+			msg:  "[]a",
+			want: "[]a",
+		},
+		{
+			name: "unclosed",
+			msg:  "a[red",
+			want: "a[red",
+		},
+		{
+			name:   "escaped bracket",
+			msg:    "[red][[x]",
+			want:   "[x]",
+			colors: []color.Color{red, red, red},
+		},
+		{
+			name: "escaped tag",
+			msg:  "[[red]",
+			want: "[red]",
+		},
+		{
+			name: "wait",
+			msg:  "a[wait=0.5]b",
+			want: "a|b",
+		},
+		{
+			name: "empty wait",
+			msg:  "a[wait=]b",
+			want: "a[wait=]b",
+		},
+		{
+			name: "zero wait",
+			msg:  "a[wait=0]b",
+			want: "a[wait=0]b",
+		},
+		{
+			name:   "unicode",
+			msg:    "[red]é☃",
+			want:   "é☃",
+			colors: []color.Color{red, red},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			chars := parseMarkup(tt.msg)
+			if got := letters(chars); got != tt.want {
+				t.Fatalf("parsed to %q, want %q", got, tt.want)
+			}
+			if tt.colors == nil {
+				tt.colors = make([]color.Color, len(chars))
+			}
+			var got []color.Color
+			for _, c := range chars {
+				got = append(got, c.color)
+			}
+			if !reflect.DeepEqual(got, tt.colors) {
+				t.Errorf("colors are %v, want %v", got, tt.colors)
+			}
+		})
+	}
+
+	chars := parseMarkup("a[wait=0.25]b")
+	if chars[1].wait != 0.25 {
+		t.Errorf("waited %v, want 0.25", chars[1].wait)
+	}
+	if got := plainText(chars); got != "ab" {
+		t.Errorf("plain text is %q, want ab", got)
+	}
+}
+
+func TestWrapMarkup(t *testing.T) {
+	for _, tt := range []struct {
+		name, msg string
+		want      []string
+	}{
+		{
+			name: "fits",
+			msg:  "short",
+			want: []string{"short"},
+		},
+		{
+			name: "at spaces",
+			msg:  "the quick brown fox",
+			want: []string{"the quick", "brown fox"},
+		},
+		{
+			name: "exactly the width",
+			msg:  "0123456789 abc",
+			want: []string{"0123456789", "abc"},
+		},
+		{
+			name: "long word",
+			msg:  "abcdefghijklmnopqrstuvwxyz",
+			want: []string{"abcdefghij", "klmnopqrst", "uvwxyz"},
+		},
+		{
+			name: "long word after a short one",
+			msg:  "a abcdefghijklm",
+			want: []string{"a", "abcdefghij", "klm"},
+		},
+		{
+			name: "newline",
+			msg:  "one\ntwo",
+			want: []string{"one", "two"},
+		},
+		{
+			name: "blank line",
+			msg:  "one\n\ntwo",
+			want: []string{"one", "", "two"},
+		},
+		{
+			// Markup and waits don't take up any room.
+			name: "markup",
+			msg:  "[red]the[/] quick[wait=1] brown",
+			want: []string{"the quick|", "brown"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range wrapMarkup(parseMarkup(tt.msg), 10, monospace) {
+				got = append(got, letters(line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapped to %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := wrapText("the quick brown fox", 10, monospace), "the quick\nbrown fox"; got != want {
+		t.Errorf("wrapText is %q, want %q", got, want)
+	}
+}
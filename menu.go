@@ -0,0 +1,316 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+var menuDisabled = color.RGBA{R: 0x70, G: 0x70, B: 0x70, A: 0xFF}
+
+// MenuEntry is one row of a ListMenu. Label is what shows in the list, Title
+// and Description show beside it while the entry is highlighted.
+type MenuEntry struct {
+	Label       string
+	Title       string
+	Description string
+	Enabled     bool
+}
+
+// ListMenu is a scrolling list with a cursor and a title and description for
+// the highlighted entry. Call Setup once, then SetEntries and Show whenever
+// it's opened and Update every frame it's open.
+type ListMenu struct {
+	// Position is where the first row goes and RowHeight is how far apart
+	// the rows are.
+	Position  engo.Point
+	RowHeight float32
+	Rows      int
+	// DetailPosition is where the title goes. The description is under it,
+	// wrapped to DescriptionWidth if that's set.
+	DetailPosition   engo.Point
+	DescriptionWidth float32
+	// Font and Scale are used for the "---" shown in empty rows.
+	Font  *common.Font
+	Scale engo.Point
+	// Wrap moves the cursor from the bottom back to the top and vice versa.
+	Wrap bool
+	// HUD draws the menu over the world, where the camera can't move it.
+	HUD bool
+
+	// OnSelect is called when A is pressed on an enabled entry, OnDisabled
+	// when it's pressed on a disabled one and OnCancel when B is pressed.
+	OnSelect   func(idx int)
+	OnDisabled func(idx int)
+	OnCancel   func()
+
+	entries        []MenuEntry
+	fnt            *common.Font
+	textScale      engo.Point
+	curIdx, topIdx int
+
+	cursor     sprite
+	rows       []*sprite
+	name, desc sprite
+	up, down   sprite
+}
+
+func (m *ListMenu) Setup(w *ecs.World) {
+	curTex, _ := common.LoadedSprite("title/cursor.png")
+	m.cursor = sprite{BasicEntity: ecs.NewBasic()}
+	m.cursor.Drawable = curTex
+	m.cursor.Width = curTex.Width()
+	m.cursor.Height = curTex.Height()
+	m.cursor.SetZIndex(3)
+	m.cursor.Hidden = true
+	w.AddEntity(&m.cursor)
+
+	m.rows = make([]*sprite, m.Rows)
+	for i := range m.rows {
+		row := &sprite{BasicEntity: ecs.NewBasic()}
+		row.Position = engo.Point{X: m.Position.X, Y: m.Position.Y + float32(i)*m.RowHeight}
+		row.SetZIndex(3)
+		row.Hidden = true
+		w.AddEntity(row)
+		m.rows[i] = row
+	}
+
+	m.name = sprite{BasicEntity: ecs.NewBasic()}
+	m.name.Position = m.DetailPosition
+	m.name.SetZIndex(3)
+	m.name.Hidden = true
+	w.AddEntity(&m.name)
+
+	m.desc = sprite{BasicEntity: ecs.NewBasic()}
+	m.desc.Position = engo.Point{X: m.DetailPosition.X, Y: m.DetailPosition.Y + m.RowHeight}
+	m.desc.SetZIndex(3)
+	m.desc.Hidden = true
+	w.AddEntity(&m.desc)
+
+	m.up = sprite{BasicEntity: ecs.NewBasic()}
+	m.up.Drawable = common.Text{
+		Font: m.Font,
+		Text: "^",
+	}
+	m.up.Scale = m.Scale
+	m.up.Position = engo.Point{X: m.Position.X - 20, Y: m.Position.Y - m.RowHeight/2}
+	m.up.SetZIndex(3)
+	m.up.Hidden = true
+	w.AddEntity(&m.up)
+
+	m.down = sprite{BasicEntity: ecs.NewBasic()}
+	m.down.Drawable = common.Text{
+		Font: m.Font,
+		Text: "v",
+	}
+	m.down.Scale = m.Scale
+	m.down.Position = engo.Point{X: m.Position.X - 20, Y: m.Position.Y + float32(m.Rows)*m.RowHeight}
+	m.down.SetZIndex(3)
+	m.down.Hidden = true
+	w.AddEntity(&m.down)
+
+	if m.HUD {
+		m.cursor.SetShader(common.HUDShader)
+		m.cursor.SetZIndex(10005)
+		for _, s := range append(m.rows, &m.name, &m.desc, &m.up, &m.down) {
+			s.SetShader(common.TextHUDShader)
+			s.SetZIndex(10005)
+		}
+	}
+}
+
+// SetEntries replaces what's in the menu, drawn with fnt at scale, and puts
+// the cursor back on the first entry.
+func (m *ListMenu) SetEntries(entries []MenuEntry, fnt *common.Font, scale engo.Point) {
+	m.entries = entries
+	m.fnt = fnt
+	m.textScale = scale
+	m.curIdx, m.topIdx = 0, 0
+	m.refresh()
+}
+
+// ReplaceEntries changes what's in the menu without moving the cursor, for
+// when entries change but stay in the same order.
+func (m *ListMenu) ReplaceEntries(entries []MenuEntry) {
+	m.entries = entries
+	m.curIdx = stepIndex(m.curIdx, 0, len(entries), false)
+	m.topIdx = scrollTop(m.curIdx, m.topIdx, m.Rows, len(entries))
+	m.refresh()
+}
+
+// Select moves the cursor to idx, scrolling the list so it's showing.
+func (m *ListMenu) Select(idx int) {
+	m.curIdx = stepIndex(idx, 0, len(m.entries), false)
+	m.topIdx = scrollTop(m.curIdx, m.topIdx, m.Rows, len(m.entries))
+	m.refresh()
+}
+
+// Selected is the index of the highlighted entry.
+func (m *ListMenu) Selected() int {
+	return m.curIdx
+}
+
+func (m *ListMenu) Update() {
+	if button("up").JustPressed() {
+		m.move(-1)
+	} else if button("down").JustPressed() {
+		m.move(1)
+	} else if button("A").JustPressed() {
+		if len(m.entries) == 0 {
+			return
+		}
+		if m.entries[m.curIdx].Enabled {
+			if m.OnSelect != nil {
+				m.OnSelect(m.curIdx)
+			}
+		} else if m.OnDisabled != nil {
+			m.OnDisabled(m.curIdx)
+		}
+	} else if button("B").JustPressed() {
+		if m.OnCancel != nil {
+			m.OnCancel()
+		}
+	}
+}
+
+func (m *ListMenu) move(d int) {
+	cur := stepIndex(m.curIdx, d, len(m.entries), m.Wrap)
+	if cur == m.curIdx {
+		return
+	}
+	m.curIdx = cur
+	m.topIdx = scrollTop(m.curIdx, m.topIdx, m.Rows, len(m.entries))
+	m.refresh()
+}
+
+// stepIndex moves cur by d in a list n long, either stopping at or wrapping
+// around the ends.
+func stepIndex(cur, d, n int, wrap bool) int {
+	if n <= 0 {
+		return 0
+	}
+	cur += d
+	if wrap {
+		cur %= n
+		if cur < 0 {
+			cur += n
+		}
+		return cur
+	}
+	if cur < 0 {
+		return 0
+	}
+	if cur >= n {
+		return n - 1
+	}
+	return cur
+}
+
+// scrollTop is the first row to show so that cur is on screen, moving the
+// window as little as possible from top.
+func scrollTop(cur, top, rows, n int) int {
+	if rows <= 0 || n <= rows {
+		return 0
+	}
+	if cur < top {
+		top = cur
+	} else if cur >= top+rows {
+		top = cur - rows + 1
+	}
+	if top > n-rows {
+		top = n - rows
+	}
+	if top < 0 {
+		top = 0
+	}
+	return top
+}
+
+func (m *ListMenu) refresh() {
+	for i, row := range m.rows {
+		idx := m.topIdx + i
+		row.Color = color.White
+		if idx >= len(m.entries) {
+			row.Drawable = common.Text{
+				Font: m.Font,
+				Text: "---",
+			}
+			row.Scale = m.Scale
+			continue
+		}
+		row.Drawable = common.Text{
+			Font: m.fnt,
+			Text: m.entries[idx].Label,
+		}
+		row.Scale = m.textScale
+		if !m.entries[idx].Enabled {
+			row.Color = menuDisabled
+		}
+	}
+
+	if len(m.entries) == 0 {
+		m.name.Drawable = common.Text{
+			Font: m.Font,
+			Text: "---",
+		}
+		m.name.Scale = engo.Point{X: m.Scale.X * 1.05, Y: m.Scale.Y * 1.05}
+		m.desc.Drawable = common.Text{
+			Font: m.Font,
+			Text: "---",
+		}
+		m.desc.Scale = engo.Point{X: m.Scale.X * 0.95, Y: m.Scale.Y * 0.95}
+	} else {
+		m.name.Drawable = common.Text{
+			Font: m.fnt,
+			Text: m.entries[m.curIdx].Title,
+		}
+		m.name.Scale = engo.Point{X: m.textScale.X * 1.05, Y: m.textScale.Y * 1.05}
+		m.desc.Scale = engo.Point{X: m.textScale.X * 0.95, Y: m.textScale.Y * 0.95}
+		desc := m.entries[m.curIdx].Description
+		if m.DescriptionWidth > 0 {
+			desc = wrapText(desc, m.DescriptionWidth, func(text string) float32 {
+				w, _, _ := m.fnt.TextDimensions(text)
+				return float32(w) * m.desc.Scale.X
+			})
+		}
+		m.desc.Drawable = common.Text{
+			Font:        m.fnt,
+			Text:        desc,
+			LineSpacing: 0.8,
+		}
+	}
+
+	if len(m.rows) > 0 {
+		row := m.rows[m.curIdx-m.topIdx]
+		m.cursor.Position = engo.Point{X: row.Position.X - m.cursor.Width - 2, Y: row.Position.Y + 5}
+	}
+	m.showIndicators()
+}
+
+func (m *ListMenu) showIndicators() {
+	m.up.Hidden = m.cursor.Hidden || m.topIdx <= 0
+	m.down.Hidden = m.cursor.Hidden || m.topIdx+m.Rows >= len(m.entries)
+}
+
+func (m *ListMenu) Show() {
+	m.cursor.Hidden = false
+	for _, row := range m.rows {
+		row.Hidden = false
+	}
+	m.name.Hidden = false
+	m.desc.Hidden = false
+	m.showIndicators()
+}
+
+func (m *ListMenu) Hide() {
+	m.cursor.Hidden = true
+	for _, row := range m.rows {
+		row.Hidden = true
+	}
+	m.name.Hidden = true
+	m.desc.Hidden = true
+	m.up.Hidden = true
+	m.down.Hidden = true
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestStepIndex(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		cur, d, n int
+		wrap      bool
+		want      int
+	}{
+		{"down", 1, 1, 5, false, 2},
+		{"up", 1, -1, 5, false, 0},
+		{"stops at the top", 0, -1, 5, false, 0},
+		{"stops at the bottom", 4, 1, 5, false, 4},
+		{"big jump stops at the bottom", 1, 10, 5, false, 4},
+		{"wraps to the bottom", 0, -1, 5, true, 4},
+		{"wraps to the top", 4, 1, 5, true, 0},
+		{"big jump wraps around", 3, 7, 5, true, 0},
+		{"big jump back wraps around", 1, -7, 5, true, 4},
+		{"one entry", 0, 1, 1, true, 0},
+		{"empty", 0, 1, 0, false, 0},
+		{"empty with wrap", 3, -1, 0, true, 0},
+	} {
+		if got := stepIndex(tt.cur, tt.d, tt.n, tt.wrap); got != tt.want {
+			t.Errorf("%v: stepIndex(%v, %v, %v, %v) = %v, want %v", tt.name, tt.cur, tt.d, tt.n, tt.wrap, got, tt.want)
+		}
+	}
+}
+
+func TestScrollTop(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		cur, top, rows, n int
+		want              int
+	}{
+		{"fewer entries than rows", 2, 1, 5, 3, 0},
+		{"as many entries as rows", 4, 0, 5, 5, 0},
+		{"no rows", 3, 2, 0, 10, 0},
+		{"empty", 0, 0, 5, 0, 0},
+		{"already on screen", 5, 3, 4, 10, 3},
+		{"off the top", 1, 3, 4, 10, 1},
+		{"off the bottom", 8, 3, 4, 10, 5},
+		{"wrapped to the top", 0, 6, 4, 10, 0},
+		{"wrapped to the bottom", 9, 0, 4, 10, 6},
+		{"top past the end after the list shrank", 2, 7, 4, 6, 2},
+	} {
+		if got := scrollTop(tt.cur, tt.top, tt.rows, tt.n); got != tt.want {
+			t.Errorf("%v: scrollTop(%v, %v, %v, %v) = %v, want %v", tt.name, tt.cur, tt.top, tt.rows, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// HUDClickMessage asks whether a HUD element is showing at Pt, in screen
+// coordinates, so a click there doesn't go through to the world.
+type HUDClickMessage struct {
+	Pt  engo.Point
+	Hit bool
+}
+
+var HUDClickMessageType = "HUD Click Message"
+
+func (*HUDClickMessage) Type() string { return HUDClickMessageType }
+
+// mouseOnHUD reports whether the mouse is over a HUD element.
+func mouseOnHUD() bool {
+	msg := &HUDClickMessage{Pt: engo.Point{X: engo.Input.Mouse.X, Y: engo.Input.Mouse.Y}}
+	engo.Mailbox.Dispatch(msg)
+	return msg.Hit
+}
+
+// shows reports whether the sprite is showing at pt.
+func (s *sprite) shows(pt engo.Point) bool {
+	if s.Hidden || s.Drawable == nil {
+		return false
+	}
+	scale := s.Scale
+	if scale.X == 0 && scale.Y == 0 {
+		scale = engo.Point{X: 1, Y: 1}
+	}
+	return pt.X >= s.Position.X && pt.Y >= s.Position.Y &&
+		pt.X < s.Position.X+s.Drawable.Width()*scale.X && pt.Y < s.Position.Y+s.Drawable.Height()*scale.Y
+}
+
+// mouseTracker follows the mouse around the world. It always counts as
+// hovered, so it's Clicked by a click anywhere.
+type mouseTracker struct {
+	ecs.BasicEntity
+	common.MouseComponent
+	common.SpaceComponent
+}
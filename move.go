@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
@@ -14,12 +16,65 @@ var TeleportPlayerMessageType = "Teleport Player Message"
 
 func (TeleportPlayerMessage) Type() string { return TeleportPlayerMessageType }
 
+// WalkPlayerMessage walks the player to Pt, calling Arrived once they get
+// there.
+type WalkPlayerMessage struct {
+	Pt      engo.Point
+	Arrived func()
+}
+
+var WalkPlayerMessageType = "Walk Player Message"
+
+func (WalkPlayerMessage) Type() string { return WalkPlayerMessageType }
+
 type MoveComponent struct {
 	PlayerCharacter bool
 	Speed           float32
+	// Patrol is the points a non-player character walks between, over and
+	// over, when it hasn't been told to walk somewhere.
+	Patrol []engo.Point
+	// FindPaths has it walk around walls with FindPath instead of straight
+	// at where it's going. It's for characters the size of the player.
+	FindPaths bool
 
 	velocity      engo.Point
 	currentZIndex float32
+
+	dest        engo.Point
+	routing     bool
+	path        []engo.Point
+	patrolIndex int
+	face        string
+	arrived     func()
+	walking     string
+	lastDist    float32
+	stuck       float32
+}
+
+// WalkTo has a non-player character stop patrolling and walk to pt, then
+// face that way ("up", "down", "left" or "right", or "" to keep facing the
+// way it walked). arrived, if it isn't nil, is called once it gets there,
+// or gives up because something's in the way.
+func (c *MoveComponent) WalkTo(pt engo.Point, face string, arrived func()) {
+	c.Patrol = nil
+	c.dest = pt
+	c.routing = true
+	c.path = nil
+	c.face = face
+	c.arrived = arrived
+	c.stuck = 0
+	c.lastDist = -1
+}
+
+// stop forgets wherever it was walking to.
+func (c *MoveComponent) stop() {
+	c.routing = false
+	c.path = nil
+	c.arrived = nil
+}
+
+func (c *MoveComponent) goingSomewhere() bool {
+	return c.routing || len(c.path) > 0
 }
 
 func (c *MoveComponent) GetMoveComponent() *MoveComponent {
@@ -47,10 +102,30 @@ type moveEntity struct {
 }
 
 type MoveSystem struct {
+	// Rooms are used to find paths for movers with FindPaths set.
+	Rooms []*worldRoom
+
 	entities []moveEntity
+
+	// mouse is where clicks walk the player to. A click that unpaused the
+	// player is skipped, since it was meant for whatever paused them.
+	mouse     *mouseTracker
+	skipClick bool
+
+	// frozen stops NPCs too, while the world's paused.
+	frozen bool
 }
 
 func (s *MoveSystem) New(w *ecs.World) {
+	s.mouse = &mouseTracker{BasicEntity: ecs.NewBasic()}
+	s.mouse.Track = true
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *common.MouseSystem:
+			sys.Add(&s.mouse.BasicEntity, &s.mouse.MouseComponent, &s.mouse.SpaceComponent, nil)
+		}
+	}
+
 	engo.Mailbox.Listen(TeleportPlayerMessageType, func(message engo.Message) {
 		msg, ok := message.(TeleportPlayerMessage)
 		if !ok {
@@ -62,10 +137,32 @@ func (s *MoveSystem) New(w *ecs.World) {
 			}
 		}
 	})
+	engo.Mailbox.Listen(WorldPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(WorldPauseMessage)
+		if !ok {
+			return
+		}
+		s.frozen = msg.Pause
+	})
+	engo.Mailbox.Listen(WalkPlayerMessageType, func(message engo.Message) {
+		msg, ok := message.(WalkPlayerMessage)
+		if !ok {
+			return
+		}
+		for i := 0; i < len(s.entities); i++ {
+			if s.entities[i].PlayerCharacter {
+				s.entities[i].WalkTo(msg.Pt, "", msg.Arrived)
+			}
+		}
+	})
 }
 
 func (s *MoveSystem) Add(basic *ecs.BasicEntity, anim *common.AnimationComponent, space *common.SpaceComponent, render *common.RenderComponent, move *MoveComponent) {
 	move.velocity = engo.Point{}
+	if move.PlayerCharacter {
+		move.stop()
+		s.skipClick = true
+	}
 	s.entities = append(s.entities, moveEntity{basic, anim, space, render, move})
 }
 
@@ -98,119 +195,253 @@ func (s *MoveSystem) Update(dt float32) {
 				v, _ = v.Normalize()
 				v.MultiplyScalar(dt * entity.Speed)
 				s.entities[i].velocity = v
+				entity.stop()
+			} else if s.floorClicked() {
+				entity.WalkTo(engo.Point{
+					X: s.mouse.MouseX - (playaFeet.Min.X+playaFeet.Max.X)/2,
+					Y: s.mouse.MouseY - (playaFeet.Min.Y+playaFeet.Max.Y)/2,
+				}, "", nil)
 			}
-			s.setAnimation(entity)
-			entity.Position.Add(entity.velocity)
-		} else {
-			//something else
+			if entity.goingSomewhere() {
+				s.walk(entity, dt)
+			} else {
+				s.setAnimation(entity)
+				entity.Position.Add(entity.velocity)
+			}
+			CurrentSave.PlayerLocation = entity.Position
+		} else if !s.frozen {
+			s.walk(entity, dt)
 		}
 		if entity.currentZIndex != entity.Position.Y {
 			entity.SetZIndex(entity.Position.Y)
 			entity.currentZIndex = entity.Position.Y
 		}
 	}
+	s.skipClick = false
+}
+
+// floorClicked reports whether the world was clicked somewhere that isn't
+// the HUD or an interest, which handle their own clicks.
+func (s *MoveSystem) floorClicked() bool {
+	if s.skipClick || !s.mouse.Clicked || mouseOnHUD() {
+		return false
+	}
+	msg := &InterestHoveredMessage{}
+	engo.Mailbox.Dispatch(msg)
+	return !msg.Hovered
+}
+
+// npcStuckTime is how long a non-player character keeps walking into a wall
+// before giving up on where it's going.
+const npcStuckTime = 1
+
+// walk moves a non-player character along its path, or its patrol once the
+// path runs out.
+func (s *MoveSystem) walk(entity moveEntity, dt float32) {
+	if entity.routing {
+		entity.routing = false
+		s.route(entity, entity.dest)
+	} else if len(entity.path) == 0 {
+		if len(entity.Patrol) == 0 {
+			return
+		}
+		entity.patrolIndex %= len(entity.Patrol)
+		s.route(entity, entity.Patrol[entity.patrolIndex])
+		entity.patrolIndex++
+	}
+
+	to := entity.path[0]
+	d := engo.Point{X: to.X - entity.Position.X, Y: to.Y - entity.Position.Y}
+	dist := entity.Position.PointDistance(to)
+	step := dt * entity.Speed
+	if dist <= step {
+		entity.Position = to
+		s.reached(entity)
+		return
+	}
+
+	// Walls push characters back, so if it's not getting any closer
+	// something's in the way.
+	if entity.lastDist >= 0 && dist >= entity.lastDist-step/2 {
+		entity.stuck += dt
+		if entity.stuck >= npcStuckTime {
+			s.reached(entity)
+			return
+		}
+	} else {
+		entity.stuck = 0
+	}
+	entity.lastDist = dist
+
+	d.MultiplyScalar(step / dist)
+	entity.Position.Add(d)
+	s.selectIfNew(entity, walkDirection(d))
+}
+
+// route sets the path an entity takes to get to to.
+func (s *MoveSystem) route(entity moveEntity, to engo.Point) {
+	entity.path = []engo.Point{to}
+	entity.lastDist = -1
+	entity.stuck = 0
+	if !entity.FindPaths {
+		return
+	}
+	if r := roomAt(s.Rooms, entity.Position); r != nil {
+		if path, ok := FindPath(r, entity.Position, to); ok {
+			entity.path = path
+		}
+	}
+}
+
+// reached moves a non-player character on to the next point in its path, and
+// faces it the way it was told to once there aren't any left.
+func (s *MoveSystem) reached(entity moveEntity) {
+	entity.path = entity.path[1:]
+	entity.lastDist = -1
+	entity.stuck = 0
+	if len(entity.path) > 0 {
+		return
+	}
+	face := entity.face
+	if face == "" {
+		face = entity.walking
+	}
+	if face != "" {
+		s.selectIfNew(entity, face+"stop")
+	}
+	entity.face = ""
+	if arrived := entity.arrived; arrived != nil {
+		entity.arrived = nil
+		arrived()
+	}
+}
+
+// selectIfNew selects the animation called name, if the entity has one and it
+// isn't already playing it. Characters without directional animations keep
+// whatever they're playing.
+func (s *MoveSystem) selectIfNew(entity moveEntity, name string) {
+	if strings.HasSuffix(name, "stop") {
+		entity.walking = ""
+	} else {
+		entity.walking = name
+	}
+	a, ok := entity.Animations[name]
+	if !ok || entity.CurrentAnimation == a {
+		return
+	}
+	entity.SelectAnimationByName(name)
+}
+
+// walkDirection is the name of the walking animation for moving by d.
+func walkDirection(d engo.Point) string {
+	if d.X*d.X > d.Y*d.Y {
+		if d.X < 0 {
+			return "left"
+		}
+		return "right"
+	}
+	if d.Y < 0 {
+		return "up"
+	}
+	return "down"
 }
 
 func (s *MoveSystem) setAnimation(entity moveEntity) {
-	if engo.Input.Button("left").JustPressed() {
+	if button("left").JustPressed() {
 		entity.AnimationComponent.SelectAnimationByName("left")
-	} else if engo.Input.Button("right").JustPressed() {
+	} else if button("right").JustPressed() {
 		entity.AnimationComponent.SelectAnimationByName("right")
-	} else if engo.Input.Button("up").JustPressed() {
+	} else if button("up").JustPressed() {
 		entity.AnimationComponent.SelectAnimationByName("up")
-	} else if engo.Input.Button("down").JustPressed() {
+	} else if button("down").JustPressed() {
 		entity.AnimationComponent.SelectAnimationByName("down")
 	}
 
-	if engo.Input.Button("up").JustReleased() {
+	if button("up").JustReleased() {
 		entity.AnimationComponent.SelectAnimationByName("upstop")
-		if engo.Input.Button("left").Down() {
+		if button("left").Down() {
 			entity.AnimationComponent.SelectAnimationByName("left")
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			entity.AnimationComponent.SelectAnimationByName("right")
-		} else if engo.Input.Button("up").Down() {
+		} else if button("up").Down() {
 			entity.AnimationComponent.SelectAnimationByName("up")
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			entity.AnimationComponent.SelectAnimationByName("down")
 		}
-	} else if engo.Input.Button("down").JustReleased() {
+	} else if button("down").JustReleased() {
 		entity.AnimationComponent.SelectAnimationByName("downstop")
-		if engo.Input.Button("left").Down() {
+		if button("left").Down() {
 			entity.AnimationComponent.SelectAnimationByName("left")
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			entity.AnimationComponent.SelectAnimationByName("right")
-		} else if engo.Input.Button("up").Down() {
+		} else if button("up").Down() {
 			entity.AnimationComponent.SelectAnimationByName("up")
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			entity.AnimationComponent.SelectAnimationByName("down")
 		}
-	} else if engo.Input.Button("left").JustReleased() {
+	} else if button("left").JustReleased() {
 		entity.AnimationComponent.SelectAnimationByName("leftstop")
-		if engo.Input.Button("left").Down() {
+		if button("left").Down() {
 			entity.AnimationComponent.SelectAnimationByName("left")
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			entity.AnimationComponent.SelectAnimationByName("right")
-		} else if engo.Input.Button("up").Down() {
+		} else if button("up").Down() {
 			entity.AnimationComponent.SelectAnimationByName("up")
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			entity.AnimationComponent.SelectAnimationByName("down")
 		}
-	} else if engo.Input.Button("right").JustReleased() {
+	} else if button("right").JustReleased() {
 		entity.AnimationComponent.SelectAnimationByName("rightstop")
-		if engo.Input.Button("left").Down() {
+		if button("left").Down() {
 			entity.AnimationComponent.SelectAnimationByName("left")
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			entity.AnimationComponent.SelectAnimationByName("right")
-		} else if engo.Input.Button("up").Down() {
+		} else if button("up").Down() {
 			entity.AnimationComponent.SelectAnimationByName("up")
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			entity.AnimationComponent.SelectAnimationByName("down")
 		}
 	}
 }
 
 func (s *MoveSystem) getSpeed() (p engo.Point, changed bool) {
-	p.X = engo.Input.Axis("horizontal").Value()
-	p.Y = engo.Input.Axis("vertical").Value()
-	origX, origY := p.X, p.Y
-
-	if engo.Input.Button("up").JustPressed() {
+	if button("up").JustPressed() {
 		p.Y = -1
-	} else if engo.Input.Button("down").JustPressed() {
+	} else if button("down").JustPressed() {
 		p.Y = 1
 	}
-	if engo.Input.Button("left").JustPressed() {
+	if button("left").JustPressed() {
 		p.X = -1
-	} else if engo.Input.Button("right").JustPressed() {
+	} else if button("right").JustPressed() {
 		p.X = 1
 	}
 
-	if engo.Input.Button("up").JustReleased() || engo.Input.Button("down").JustReleased() {
+	if button("up").JustReleased() || button("down").JustReleased() {
 		p.Y = 0
 		changed = true
-		if engo.Input.Button("up").Down() {
+		if button("up").Down() {
 			p.Y = -1
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			p.Y = 1
-		} else if engo.Input.Button("left").Down() {
+		} else if button("left").Down() {
 			p.X = -1
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			p.X = 1
 		}
 	}
-	if engo.Input.Button("left").JustReleased() || engo.Input.Button("right").JustReleased() {
+	if button("left").JustReleased() || button("right").JustReleased() {
 		p.X = 0
 		changed = true
-		if engo.Input.Button("left").Down() {
+		if button("left").Down() {
 			p.X = -1
-		} else if engo.Input.Button("right").Down() {
+		} else if button("right").Down() {
 			p.X = 1
-		} else if engo.Input.Button("up").Down() {
+		} else if button("up").Down() {
 			p.Y = -1
-		} else if engo.Input.Button("down").Down() {
+		} else if button("down").Down() {
 			p.Y = 1
 		}
 	}
-	changed = changed || p.X != origX || p.Y != origY
+	changed = changed || p.X != 0 || p.Y != 0
 	return
 }
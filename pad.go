@@ -0,0 +1,213 @@
+package main
+
+import (
+	"log"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+)
+
+// PadButton is a button on a gamepad.
+type PadButton uint8
+
+const (
+	PadA PadButton = iota
+	PadB
+	PadX
+	PadY
+	PadUp
+	PadDown
+	PadLeft
+	PadRight
+	PadStart
+	PadBack
+)
+
+// Pad is a gamepad. engoPad is a real one, and anything else that acts like
+// one can stand in for it.
+type Pad interface {
+	// Connect looks for the pad if it hasn't been found yet, and reports
+	// whether it's still plugged in.
+	Connect() bool
+	// Down reports whether b is held.
+	Down(b PadButton) bool
+	// Stick is how far the movement stick is pushed, from -1 to 1 on each
+	// axis, with down being positive like the world's Y.
+	Stick() engo.Point
+}
+
+// CurrentPad is the pad every scene reads.
+var CurrentPad Pad = &engoPad{name: "Player 1"}
+
+// padBindings are the pad buttons for each action. The stick pushes the
+// direction actions too.
+var padBindings = map[string][]PadButton{
+	"up":       {PadUp},
+	"down":     {PadDown},
+	"left":     {PadLeft},
+	"right":    {PadRight},
+	"A":        {PadA},
+	"B":        {PadB},
+	"X":        {PadX},
+	"Y":        {PadY},
+	"Controls": {PadBack},
+}
+
+// padDeadzone is how far the stick has to be pushed before it counts.
+const padDeadzone = 0.3
+
+// padHeld are the actions the pad is holding this frame, and padWasHeld the
+// ones it held last frame.
+var padHeld, padWasHeld = map[string]bool{}, map[string]bool{}
+
+// padActions are the actions p is holding.
+func padActions(p Pad) map[string]bool {
+	held := make(map[string]bool)
+	for action, buttons := range padBindings {
+		for _, b := range buttons {
+			if p.Down(b) {
+				held[action] = true
+			}
+		}
+	}
+	up, down, left, right := stickDirections(p.Stick())
+	held["up"] = held["up"] || up
+	held["down"] = held["down"] || down
+	held["left"] = held["left"] || left
+	held["right"] = held["right"] || right
+	return held
+}
+
+// stickDirections are the directions the stick is pushed. A direction counts
+// once the stick is past the deadzone and no more than 60 degrees off it, so
+// diagonals hold two directions but a slightly crooked push holds one.
+func stickDirections(s engo.Point) (up, down, left, right bool) {
+	length := s.PointDistance(engo.Point{})
+	if length < padDeadzone {
+		return
+	}
+	min := length / 2
+	return s.Y <= -min, s.Y >= min, s.X <= -min, s.X >= min
+}
+
+// actionButton is an action's keys and pad buttons together. It has the same
+// methods as engo.Button, so use button instead of engo.Input.Button for
+// anything the player does.
+type actionButton struct {
+	name string
+	key  engo.Button
+}
+
+func button(name string) actionButton {
+	return actionButton{name: name, key: engo.Input.Button(name)}
+}
+
+func (b actionButton) Down() bool {
+	return b.key.Down() || padHeld[b.name]
+}
+
+func (b actionButton) JustPressed() bool {
+	return b.key.JustPressed() || padHeld[b.name] && !padWasHeld[b.name]
+}
+
+func (b actionButton) JustReleased() bool {
+	if padHeld[b.name] {
+		return false
+	}
+	return b.key.JustReleased() || padWasHeld[b.name] && !b.key.Down()
+}
+
+// engoPad is a gamepad found through engo.
+type engoPad struct {
+	name string
+	pad  *engo.Gamepad
+}
+
+func (p *engoPad) Connect() bool {
+	if p.pad != nil && !padPresentImpl() {
+		// Unplugged. It's registered again once something's plugged back in.
+		p.pad = nil
+	}
+	if p.pad == nil {
+		if !padPresentImpl() {
+			return false
+		}
+		if err := engo.Input.RegisterGamepad(p.name); err != nil {
+			return false
+		}
+		p.pad = engo.Input.Gamepad(p.name)
+	}
+	return p.pad != nil
+}
+
+func (p *engoPad) Down(b PadButton) bool {
+	if p.pad == nil {
+		return false
+	}
+	switch b {
+	case PadA:
+		return p.pad.A.Down()
+	case PadB:
+		return p.pad.B.Down()
+	case PadX:
+		return p.pad.X.Down()
+	case PadY:
+		return p.pad.Y.Down()
+	case PadUp:
+		return p.pad.DpadUp.Down()
+	case PadDown:
+		return p.pad.DpadDown.Down()
+	case PadLeft:
+		return p.pad.DpadLeft.Down()
+	case PadRight:
+		return p.pad.DpadRight.Down()
+	case PadStart:
+		return p.pad.Start.Down()
+	case PadBack:
+		return p.pad.Back.Down()
+	}
+	return false
+}
+
+func (p *engoPad) Stick() engo.Point {
+	if p.pad == nil {
+		return engo.Point{}
+	}
+	return engo.Point{X: p.pad.LeftX.Value(), Y: p.pad.LeftY.Value()}
+}
+
+// padPollTime is how often, in seconds, the PadSystem checks whether the pad
+// has been plugged in or unplugged.
+const padPollTime = 1
+
+// PadSystem reads CurrentPad into the actions every frame. Add it to a scene
+// before anything that reads buttons.
+type PadSystem struct {
+	connected bool
+	elapsed   float32
+}
+
+func (s *PadSystem) New(w *ecs.World) {
+	s.connected = CurrentPad.Connect()
+}
+
+func (s *PadSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *PadSystem) Update(dt float32) {
+	s.elapsed += dt
+	if s.elapsed >= padPollTime {
+		s.elapsed = 0
+		was := s.connected
+		s.connected = CurrentPad.Connect()
+		if s.connected && !was {
+			log.Println("Gamepad connected.")
+		} else if was && !s.connected {
+			log.Println("Gamepad disconnected.")
+		}
+	}
+	padWasHeld = padHeld
+	padHeld = map[string]bool{}
+	if s.connected {
+		padHeld = padActions(CurrentPad)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build js
+// +build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// padPresentImpl reports whether any gamepad is plugged in.
+func padPresentImpl() bool {
+	nav := js.Global().Get("navigator")
+	if !nav.Get("getGamepads").Truthy() {
+		return false
+	}
+	pads := nav.Call("getGamepads")
+	for i := 0; i < pads.Length(); i++ {
+		if p := pads.Index(i); p.Truthy() && p.Get("connected").Bool() {
+			return true
+		}
+	}
+	return false
+}
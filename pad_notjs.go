@@ -0,0 +1,18 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// padPresentImpl reports whether any gamepad is plugged in.
+func padPresentImpl() bool {
+	for j := glfw.Joystick1; j <= glfw.JoystickLast; j++ {
+		if j.IsGamepad() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+// fakePad is a Pad the test holds the buttons of.
+type fakePad struct {
+	plugged bool
+	down    map[PadButton]bool
+	stick   engo.Point
+}
+
+func (p *fakePad) Connect() bool          { return p.plugged }
+func (p *fakePad) Down(b PadButton) bool  { return p.plugged && p.down[b] }
+func (p *fakePad) Stick() engo.Point      { return p.stick }
+func (p *fakePad) press(b ...PadButton)   { p.set(true, b) }
+func (p *fakePad) release(b ...PadButton) { p.set(false, b) }
+
+func (p *fakePad) set(down bool, buttons []PadButton) {
+	if p.down == nil {
+		p.down = make(map[PadButton]bool)
+	}
+	for _, b := range buttons {
+		p.down[b] = down
+	}
+}
+
+// useFakePad swaps in a fake pad for the rest of the test.
+func useFakePad(t *testing.T) *fakePad {
+	old := CurrentPad
+	p := &fakePad{plugged: true}
+	CurrentPad = p
+	t.Cleanup(func() {
+		CurrentPad = old
+		padHeld, padWasHeld = map[string]bool{}, map[string]bool{}
+	})
+	return p
+}
+
+func TestStickDirections(t *testing.T) {
+	for _, tt := range []struct {
+		name                  string
+		stick                 engo.Point
+		up, down, left, right bool
+	}{
+		{"centered", engo.Point{}, false, false, false, false},
+		{"in the deadzone", engo.Point{X: 0.2, Y: -0.1}, false, false, false, false},
+		{"up", engo.Point{Y: -1}, true, false, false, false},
+		{"down", engo.Point{Y: 0.5}, false, true, false, false},
+		{"left", engo.Point{X: -0.8}, false, false, true, false},
+		{"right", engo.Point{X: 1}, false, false, false, true},
+		{"diagonal", engo.Point{X: 0.7, Y: 0.7}, false, true, false, true},
+		{"a bit crooked", engo.Point{X: 0.9, Y: -0.3}, false, false, false, true},
+	} {
+		up, down, left, right := stickDirections(tt.stick)
+		if up != tt.up || down != tt.down || left != tt.left || right != tt.right {
+			t.Errorf("%v: got up %v down %v left %v right %v", tt.name, up, down, left, right)
+		}
+	}
+}
+
+func TestPadActions(t *testing.T) {
+	p := &fakePad{plugged: true}
+	p.press(PadA, PadBack, PadStart)
+	p.stick = engo.Point{X: -1}
+	want := map[string]bool{"A": true, "Controls": true, "left": true}
+	got := padActions(p)
+	for action, held := range got {
+		if held != want[action] {
+			t.Errorf("%v held is %v, want %v", action, held, want[action])
+		}
+	}
+	for action := range want {
+		if !got[action] {
+			t.Errorf("%v isn't held", action)
+		}
+	}
+
+	// The d-pad and stick both push the same direction.
+	p.press(PadLeft)
+	if !padActions(p)["left"] {
+		t.Error("left isn't held with the d-pad and stick both pushed")
+	}
+}
+
+func TestPadButton(t *testing.T) {
+	p := useFakePad(t)
+	var s PadSystem
+	s.New(nil)
+
+	p.press(PadA)
+	s.Update(0.01)
+	if a := button("A"); !a.Down() || !a.JustPressed() || a.JustReleased() {
+		t.Errorf("A is down %v, just pressed %v and just released %v on the frame it's pressed", a.Down(), a.JustPressed(), a.JustReleased())
+	}
+	s.Update(0.01)
+	if a := button("A"); !a.Down() || a.JustPressed() {
+		t.Errorf("A is down %v and just pressed %v while held", a.Down(), a.JustPressed())
+	}
+	p.release(PadA)
+	s.Update(0.01)
+	if a := button("A"); a.Down() || !a.JustReleased() {
+		t.Errorf("A is down %v and just released %v once let go", a.Down(), a.JustReleased())
+	}
+	if button("B").Down() {
+		t.Error("B is down without being pressed")
+	}
+}
+
+func TestPadSystemReconnects(t *testing.T) {
+	p := useFakePad(t)
+	var s PadSystem
+	s.New(nil)
+	p.press(PadB)
+	s.Update(0.01)
+	if !button("B").Down() {
+		t.Fatal("B isn't down")
+	}
+
+	p.plugged = false
+	s.Update(padPollTime)
+	if s.connected {
+		t.Error("still connected after the pad was unplugged")
+	}
+	if button("B").Down() {
+		t.Error("B is still down after the pad was unplugged")
+	}
+
+	p.plugged = true
+	s.Update(padPollTime * 0.5)
+	if button("B").Down() {
+		t.Error("pad was read before the next time it's looked for")
+	}
+	s.Update(padPollTime * 0.5)
+	if !s.connected || !button("B").Down() {
+		t.Errorf("connected is %v and B down is %v after plugging the pad back in", s.connected, button("B").Down())
+	}
+}
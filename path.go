@@ -0,0 +1,232 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/EngoEngine/engo"
+)
+
+// navCellSize is how far apart, in pixels, the spots FindPath looks at are.
+const navCellSize = 8
+
+// navGrid is every spot in a room the player can stand, by their position.
+type navGrid struct {
+	start engo.Point
+	w, h  int
+	open  []bool
+}
+
+// newNavGrid works out where in r the player's feet don't touch a wall or
+// stick out of the room.
+func newNavGrid(r *worldRoom) *navGrid {
+	g := &navGrid{
+		start: r.Start,
+		w:     int(r.Size.X/navCellSize) + 1,
+		h:     int(r.Size.Y/navCellSize) + 1,
+	}
+	g.open = make([]bool, g.w*g.h)
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			g.open[y*g.w+x] = standable(r.roomInfo, engo.Point{X: float32(x * navCellSize), Y: float32(y * navCellSize)})
+		}
+	}
+	return g
+}
+
+// standable reports whether the player can stand at pt, in room coordinates.
+func standable(info roomInfo, pt engo.Point) bool {
+	feet := engo.AABB{
+		Min: engo.Point{X: pt.X + playaFeet.Min.X, Y: pt.Y + playaFeet.Min.Y},
+		Max: engo.Point{X: pt.X + playaFeet.Max.X, Y: pt.Y + playaFeet.Max.Y},
+	}
+	if feet.Min.X < 0 || feet.Min.Y < 0 || feet.Max.X > info.Size.X || feet.Max.Y > info.Size.Y {
+		return false
+	}
+	for _, w := range info.Walls {
+		if wallOverlaps(w, feet) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *navGrid) isOpen(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.w && y < g.h && g.open[y*g.w+x]
+}
+
+// cell is the cell nearest pt, in world coordinates.
+func (g *navGrid) cell(pt engo.Point) (int, int) {
+	x := int(math.Floor(float64((pt.X-g.start.X)/navCellSize + 0.5)))
+	y := int(math.Floor(float64((pt.Y-g.start.Y)/navCellSize + 0.5)))
+	return x, y
+}
+
+func (g *navGrid) point(x, y int) engo.Point {
+	return engo.Point{X: g.start.X + float32(x*navCellSize), Y: g.start.Y + float32(y*navCellSize)}
+}
+
+// nearestOpen is the open cell closest to x, y, or false if there aren't any.
+func (g *navGrid) nearestOpen(x, y int) (int, int, bool) {
+	if g.isOpen(x, y) {
+		return x, y, true
+	}
+	bx, by, best := 0, 0, -1
+	for cy := 0; cy < g.h; cy++ {
+		for cx := 0; cx < g.w; cx++ {
+			if !g.open[cy*g.w+cx] {
+				continue
+			}
+			d := (cx-x)*(cx-x) + (cy-y)*(cy-y)
+			if best < 0 || d < best {
+				bx, by, best = cx, cy, d
+			}
+		}
+	}
+	return bx, by, best >= 0
+}
+
+// FindPath finds a way for the player, or something their size, to walk from
+// from to to in room, going around its walls. Both points and the path are
+// in world coordinates. The path doesn't include from, and if to can't be
+// stood on it ends at the closest spot that can. It returns false if there's
+// no way there.
+func FindPath(room *worldRoom, from, to engo.Point) ([]engo.Point, bool) {
+	if room.nav == nil {
+		room.nav = newNavGrid(room)
+	}
+	g := room.nav
+
+	fx, fy := g.cell(from)
+	fx, fy, ok := g.nearestOpen(fx, fy)
+	if !ok {
+		return nil, false
+	}
+	tx, ty := g.cell(to)
+	goal := to
+	if !g.isOpen(tx, ty) || !standable(room.roomInfo, engo.Point{X: to.X - room.Start.X, Y: to.Y - room.Start.Y}) {
+		if tx, ty, ok = g.nearestOpen(tx, ty); !ok {
+			return nil, false
+		}
+		goal = g.point(tx, ty)
+	}
+
+	cells := g.search(fx, fy, tx, ty)
+	if cells == nil {
+		return nil, false
+	}
+	path := make([]engo.Point, 0, len(cells))
+	for _, c := range g.smooth(cells) {
+		path = append(path, g.point(c%g.w, c/g.w))
+	}
+	// The first cell is where from is, so it's dropped, and the last one is
+	// swapped for exactly where they're going.
+	if len(path) < 2 {
+		return []engo.Point{goal}, true
+	}
+	return append(path[1:len(path)-1], goal), true
+}
+
+// search runs A* from one cell to another, going diagonally only when it
+// doesn't cut a corner. It returns the cells on the way, by index and
+// including both ends, or nil if there's no way through.
+func (g *navGrid) search(fx, fy, tx, ty int) []int {
+	start, goal := fy*g.w+fx, ty*g.w+tx
+	cost := map[int]float64{start: 0}
+	from := map[int]int{}
+	open := &navQueue{{cell: start, est: g.guess(fx, fy, tx, ty)}}
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(navItem).cell
+		if cur == goal {
+			cells := []int{cur}
+			for cur != start {
+				cur = from[cur]
+				cells = append([]int{cur}, cells...)
+			}
+			return cells
+		}
+		x, y := cur%g.w, cur/g.w
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx == 0 && dy == 0 || !g.isOpen(x+dx, y+dy) {
+					continue
+				}
+				step := 1.0
+				if dx != 0 && dy != 0 {
+					if !g.isOpen(x+dx, y) || !g.isOpen(x, y+dy) {
+						continue
+					}
+					step = math.Sqrt2
+				}
+				next := (y+dy)*g.w + x + dx
+				c := cost[cur] + step
+				if old, seen := cost[next]; seen && old <= c {
+					continue
+				}
+				cost[next] = c
+				from[next] = cur
+				heap.Push(open, navItem{cell: next, est: c + g.guess(x+dx, y+dy, tx, ty)})
+			}
+		}
+	}
+	return nil
+}
+
+// guess is the octile distance between two cells, which A* uses as its
+// estimate of what's left.
+func (g *navGrid) guess(x1, y1, x2, y2 int) float64 {
+	dx := math.Abs(float64(x1 - x2))
+	dy := math.Abs(float64(y1 - y2))
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+// smooth drops cells that can be skipped by walking straight to a later one,
+// so paths go at any angle instead of just the eight grid directions.
+func (g *navGrid) smooth(cells []int) []int {
+	out := []int{cells[0]}
+	for i := 0; i < len(cells)-1; {
+		j := len(cells) - 1
+		for j > i+1 && !g.clear(cells[i], cells[j]) {
+			j--
+		}
+		out = append(out, cells[j])
+		i = j
+	}
+	return out
+}
+
+// clear reports whether every cell along the line between a and b is open,
+// checking the ones either side too so the line can't squeeze past a corner.
+func (g *navGrid) clear(a, b int) bool {
+	ax, ay := float64(a%g.w), float64(a/g.w)
+	bx, by := float64(b%g.w), float64(b/g.w)
+	steps := int(math.Ceil(math.Max(math.Abs(bx-ax), math.Abs(by-ay)) * 2))
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x, y := ax+(bx-ax)*t, ay+(by-ay)*t
+		if !g.isOpen(int(math.Floor(x)), int(math.Floor(y))) || !g.isOpen(int(math.Ceil(x)), int(math.Ceil(y))) ||
+			!g.isOpen(int(math.Floor(x)), int(math.Ceil(y))) || !g.isOpen(int(math.Ceil(x)), int(math.Floor(y))) {
+			return false
+		}
+	}
+	return true
+}
+
+type navItem struct {
+	cell int
+	est  float64
+}
+
+// navQueue is a heap of cells to look at, cheapest first.
+type navQueue []navItem
+
+func (q navQueue) Len() int            { return len(q) }
+func (q navQueue) Less(i, j int) bool  { return q[i].est < q[j].est }
+func (q navQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *navQueue) Push(x interface{}) { *q = append(*q, x.(navItem)) }
+func (q *navQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+// loadLobby loads the lobby's map, put somewhere other than the origin so
+// the world coordinates get checked too.
+func loadLobby(t *testing.T) *worldRoom {
+	t.Helper()
+	data, err := ioutil.ReadFile("assets/maps/lobby.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseTiledRoom(data, "maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &worldRoom{
+		roomPlacement: roomPlacement{Name: "lobby", Start: engo.Point{X: 40, Y: 500}},
+		roomInfo:      info,
+	}
+}
+
+// lobbyPoint is the world position of a cell in the lobby's nav grid.
+func lobbyPoint(r *worldRoom, x, y int) engo.Point {
+	return engo.Point{X: r.Start.X + float32(x*navCellSize), Y: r.Start.Y + float32(y*navCellSize)}
+}
+
+// checkWalkable fails if walking straight along path, starting at from,
+// would put the player's feet in a wall.
+func checkWalkable(t *testing.T, r *worldRoom, from engo.Point, path []engo.Point) {
+	t.Helper()
+	prev := from
+	for _, pt := range path {
+		d := engo.Point{X: pt.X - prev.X, Y: pt.Y - prev.Y}
+		steps := int(math.Hypot(float64(d.X), float64(d.Y))) + 1
+		for i := 0; i <= steps; i++ {
+			f := float32(i) / float32(steps)
+			at := engo.Point{X: prev.X + d.X*f - r.Start.X, Y: prev.Y + d.Y*f - r.Start.Y}
+			if !standable(r.roomInfo, at) {
+				t.Fatalf("path %v walks into a wall at %v", path, at)
+			}
+		}
+		prev = pt
+	}
+}
+
+func TestFindPathAroundWalls(t *testing.T) {
+	r := loadLobby(t)
+	// From the bottom left of the lobby to the top right, which has the
+	// sand table in the way.
+	from, to := lobbyPoint(r, 3, 20), lobbyPoint(r, 58, 12)
+	path, ok := FindPath(r, from, to)
+	if !ok {
+		t.Fatal("didn't find a path")
+	}
+	g := r.nav
+	fx, fy := g.cell(from)
+	tx, ty := g.cell(to)
+	if g.clear(fy*g.w+fx, ty*g.w+tx) {
+		t.Fatal("the sand table should be in the way of walking straight there")
+	}
+	if len(path) < 2 {
+		t.Errorf("path %v goes straight there", path)
+	}
+	if path[len(path)-1] != to {
+		t.Errorf("path ends at %v, want %v", path[len(path)-1], to)
+	}
+	checkWalkable(t, r, from, path)
+}
+
+func TestFindPathStraight(t *testing.T) {
+	r := loadLobby(t)
+	from, to := lobbyPoint(r, 20, 10), lobbyPoint(r, 40, 12)
+	// Off the grid, so it's swapped in for the last cell exactly.
+	to.X += 3
+	path, ok := FindPath(r, from, to)
+	if !ok {
+		t.Fatal("didn't find a path")
+	}
+	if len(path) != 1 || path[0] != to {
+		t.Errorf("path is %v, want just %v", path, to)
+	}
+}
+
+func TestFindPathIntoWall(t *testing.T) {
+	r := loadLobby(t)
+	from := lobbyPoint(r, 20, 10)
+	// Feet right in the middle of the Mars globe.
+	to := engo.Point{
+		X: r.Start.X + 482 - (playaFeet.Min.X+playaFeet.Max.X)/2,
+		Y: r.Start.Y + 132 - (playaFeet.Min.Y+playaFeet.Max.Y)/2,
+	}
+	path, ok := FindPath(r, from, to)
+	if !ok {
+		t.Fatal("didn't find a path")
+	}
+	end := path[len(path)-1]
+	if end == to {
+		t.Error("path ends inside of the globe")
+	}
+	checkWalkable(t, r, from, path)
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	r := loadLobby(t)
+	from := lobbyPoint(r, 20, 10)
+	// The pocket between the sand table and the bottom right corner is
+	// walled off on every side.
+	to := lobbyPoint(r, 66, 19)
+	if g := newNavGrid(r); !g.isOpen(g.cell(to)) {
+		t.Fatal("the pocket should be somewhere the player can stand")
+	}
+	if path, ok := FindPath(r, from, to); ok {
+		t.Errorf("found path %v into the walled off pocket", path)
+	}
+}
+
+func TestNavSmooth(t *testing.T) {
+	r := loadLobby(t)
+	g := newNavGrid(r)
+	cells := g.search(3, 20, 58, 12)
+	if cells == nil {
+		t.Fatal("no path")
+	}
+	out := g.smooth(cells)
+	if out[0] != cells[0] || out[len(out)-1] != cells[len(cells)-1] {
+		t.Errorf("smoothing moved the ends from %v and %v to %v and %v", cells[0], cells[len(cells)-1], out[0], out[len(out)-1])
+	}
+	if len(out) >= len(cells) {
+		t.Errorf("smoothing kept %v of %v cells", len(out), len(cells))
+	}
+	for i := 1; i < len(out); i++ {
+		if !g.clear(out[i-1], out[i]) {
+			t.Errorf("can't walk straight from cell %v to %v", out[i-1], out[i])
+		}
+	}
+
+	// A path that's already straight smooths down to its ends.
+	line := []int{10*g.w + 20, 10*g.w + 21, 10*g.w + 22, 10*g.w + 23}
+	if out := g.smooth(line); len(out) != 2 || out[0] != line[0] || out[1] != line[3] {
+		t.Errorf("straight line smoothed to %v", out)
+	}
+}
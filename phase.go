@@ -19,20 +19,67 @@ const (
 	AbilitySelectPhase
 	ItemSelectPhase
 	TargetPhase
+	ResultPhase
+	// RunPhase calls the PhaseSetMessage's Func and moves straight on.
+	RunPhase
+	// WaitPhase waits for something else to dequeue it, like an NPC
+	// getting where it's walking to.
+	WaitPhase
+	// ControlsPhase shows the controls menu until it's closed.
+	ControlsPhase
+	// BacklogPhase shows the message history until it's closed.
+	BacklogPhase
+	// KeypadPhase shows the keypad until a code is entered or it's closed.
+	KeypadPhase
 )
 
 var PhaseSetMessageType = "Phase Set Message"
 
 type PhaseSetMessage struct {
 	Phase
+	Func func()
 }
 
 func (PhaseSetMessage) Type() string { return PhaseSetMessageType }
 
+var PhaseInterruptMessageType = "Phase Interrupt Message"
+
+// PhaseInterruptMessage plays Phase right away instead of queueing it. When
+// it's dequeued, the phase it interrupted starts over where it was.
+type PhaseInterruptMessage struct {
+	Phase
+}
+
+func (PhaseInterruptMessage) Type() string { return PhaseInterruptMessageType }
+
+var WorldPauseMessageType = "World Pause Message"
+
+// WorldPauseMessage stops everything that doesn't wait on a phase, like NPCs
+// walking and fight bars filling up.
+type WorldPauseMessage struct {
+	Pause bool
+}
+
+func (WorldPauseMessage) Type() string { return WorldPauseMessageType }
+
 var AcceptSetMessageType = "Accept Set Message"
 
+// AcceptSetMessage sets up the choice box for the next AcceptPhase. Without
+// any Choices it's a yes/no box, with yes calling AcceptFunc. Columns lays
+// the choices out in a grid that many wide, 1 being a list down the box and
+// 0 all of them in a row. Chosen is told the index of whatever's picked.
 type AcceptSetMessage struct {
 	AcceptFunc func()
+	Choices    []Choice
+	Columns    int
+	Chosen     func(idx int)
+}
+
+func (m AcceptSetMessage) choices() []Choice {
+	if m.Choices == nil {
+		return yesNo(m.AcceptFunc)
+	}
+	return m.Choices
 }
 
 func (AcceptSetMessage) Type() string { return AcceptSetMessageType }
@@ -50,11 +97,13 @@ type PhaseSystem struct {
 	cursor *CursorSystem
 
 	currentPhase, setPhase Phase
-	queue                  []Phase
+	setFunc                func()
+	queue                  []PhaseSetMessage
 	lock                   sync.Mutex
 
-	acceptFunc    func()
+	accept        AcceptSetMessage
 	acceptLogWait bool
+	keypadLogWait bool
 }
 
 func (s *PhaseSystem) New(w *ecs.World) {
@@ -76,9 +125,28 @@ func (s *PhaseSystem) New(w *ecs.World) {
 		defer s.lock.Unlock()
 		if s.currentPhase == BeginingPhase && s.setPhase == BeginingPhase {
 			s.setPhase = msg.Phase
+			s.setFunc = msg.Func
 		} else {
-			s.queue = append(s.queue, msg.Phase)
+			s.queue = append(s.queue, msg)
+		}
+	})
+
+	engo.Mailbox.Listen(PhaseInterruptMessageType, func(message engo.Message) {
+		msg, ok := message.(PhaseInterruptMessage)
+		if !ok {
+			return
+		}
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.menuOpen(s.currentPhase) || s.menuOpen(s.setPhase) {
+			return
+		}
+		// With nothing going on, there's nothing to go back to.
+		if s.setPhase != BeginingPhase {
+			s.queue = append([]PhaseSetMessage{{Phase: s.setPhase, Func: s.setFunc}}, s.queue...)
 		}
+		s.setPhase = msg.Phase
+		s.setFunc = nil
 	})
 
 	engo.Mailbox.Listen(AcceptSetMessageType, func(message engo.Message) {
@@ -86,7 +154,7 @@ func (s *PhaseSystem) New(w *ecs.World) {
 		if !ok {
 			return
 		}
-		s.acceptFunc = msg.AcceptFunc
+		s.accept = msg
 	})
 
 	engo.Mailbox.Listen(PhaseDequeuMessageType, func(message engo.Message) {
@@ -105,39 +173,24 @@ func (s *PhaseSystem) AddByInterface(i ecs.Identifier) {
 }
 
 func (s *PhaseSystem) Update(dt float32) {
+	if s.setPhase == RunPhase {
+		if s.currentPhase != RunPhase {
+			s.pauseAll()
+			s.currentPhase = RunPhase
+		}
+		f := s.setFunc
+		s.setFunc = nil
+		if f != nil {
+			f()
+		}
+		s.dequeue()
+		return
+	}
 	if s.currentPhase != s.setPhase {
-		for _, entity := range s.entities {
-			if mover, ok := entity.(Moveable); ok {
-				s.move.Remove(*mover.GetBasicEntity())
-			}
-			if cur, ok := entity.(CursorAble); ok {
-				s.cursor.Remove(*cur.GetBasicEntity())
-			}
+		if s.pausesWorld(s.currentPhase) {
+			engo.Mailbox.Dispatch(WorldPauseMessage{Pause: false})
 		}
-		engo.Mailbox.Dispatch(CombatLogPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(DoorSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(InterestSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(AcceptSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(CardSelectSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(AbilitySelectSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(ItemSelectSystemPauseMessage{
-			Pause: true,
-		})
-		engo.Mailbox.Dispatch(TargetSystemPauseMessage{
-			Pause: true,
-		})
+		s.pauseAll()
 		switch s.setPhase {
 		case ListenPhase:
 			engo.Mailbox.Dispatch(CombatLogPauseMessage{
@@ -145,7 +198,7 @@ func (s *PhaseSystem) Update(dt float32) {
 			})
 		case WalkPhase:
 			for _, entity := range s.entities {
-				if mover, ok := entity.(Moveable); ok {
+				if mover, ok := entity.(Moveable); ok && mover.GetMoveComponent().PlayerCharacter {
 					s.move.AddByInterface(mover)
 				}
 			}
@@ -155,6 +208,20 @@ func (s *PhaseSystem) Update(dt float32) {
 			engo.Mailbox.Dispatch(InterestSystemPauseMessage{
 				Pause: false,
 			})
+			engo.Mailbox.Dispatch(ControlsSystemPauseMessage{
+				Pause: false,
+			})
+		case ControlsPhase:
+			engo.Mailbox.Dispatch(ControlsMenuMessage{})
+		case BacklogPhase:
+			engo.Mailbox.Dispatch(WorldPauseMessage{Pause: true})
+			engo.Mailbox.Dispatch(BacklogMenuMessage{})
+		case KeypadPhase:
+			engo.Mailbox.Dispatch(WorldPauseMessage{Pause: true})
+			engo.Mailbox.Dispatch(CombatLogPauseMessage{
+				Pause: false,
+			})
+			s.keypadLogWait = true
 		case LogClearPhase:
 			engo.Mailbox.Dispatch(CombatLogClearMessage{})
 		case AcceptPhase:
@@ -164,21 +231,30 @@ func (s *PhaseSystem) Update(dt float32) {
 			s.acceptLogWait = true
 		case CardSelectPhase:
 			engo.Mailbox.Dispatch(CardSelectSystemPauseMessage{Pause: false})
+			engo.Mailbox.Dispatch(AISystemPauseMessage{Pause: false})
 			engo.Mailbox.Dispatch(CombatLogPauseMessage{
 				Pause: false,
 			})
 		case AbilitySelectPhase:
 			engo.Mailbox.Dispatch(AbilitySelectSystemPauseMessage{Pause: false})
+			engo.Mailbox.Dispatch(AISystemPauseMessage{Pause: false})
 			engo.Mailbox.Dispatch(CombatLogPauseMessage{
 				Pause: false,
 			})
 		case ItemSelectPhase:
 			engo.Mailbox.Dispatch(ItemSelectSystemPauseMessage{Pause: false})
+			engo.Mailbox.Dispatch(AISystemPauseMessage{Pause: false})
 			engo.Mailbox.Dispatch(CombatLogPauseMessage{
 				Pause: false,
 			})
 		case TargetPhase:
 			engo.Mailbox.Dispatch(TargetSystemPauseMessage{Pause: false})
+			engo.Mailbox.Dispatch(AISystemPauseMessage{Pause: false})
+			engo.Mailbox.Dispatch(CombatLogPauseMessage{
+				Pause: false,
+			})
+		case ResultPhase:
+			engo.Mailbox.Dispatch(ResultSystemPauseMessage{Pause: false})
 			engo.Mailbox.Dispatch(CombatLogPauseMessage{
 				Pause: false,
 			})
@@ -191,8 +267,8 @@ func (s *PhaseSystem) Update(dt float32) {
 		if !s.logDone() {
 			return
 		}
-		if engo.Input.Button("A").JustPressed() || engo.Input.Button("B").JustPressed() ||
-			engo.Input.Button("X").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft) {
+		if button("A").JustPressed() || button("B").JustPressed() ||
+			button("X").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft) {
 			s.dequeue()
 		}
 	case WalkPhase:
@@ -210,17 +286,17 @@ func (s *PhaseSystem) Update(dt float32) {
 				s.acceptLogWait = false
 			}
 		}
-		if engo.Input.Button("A").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft) {
-			accept := &YesSelectedMessage{}
-			engo.Mailbox.Dispatch(accept)
-			if accept.Selected && s.acceptFunc != nil {
-				s.acceptFunc()
-				s.dequeue()
-			} else {
-				s.dequeue()
+		choices := s.accept.choices()
+		if button("A").JustPressed() || (engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft) {
+			picked := &ChoiceSelectedMessage{Index: -1}
+			engo.Mailbox.Dispatch(picked)
+			if picked.Index >= 0 && choices[picked.Index].Disabled {
+				return
 			}
-		}
-		if engo.Input.Button("B").JustPressed() {
+			s.choose(choices, picked.Index)
+			s.dequeue()
+		} else if button("B").JustPressed() {
+			s.choose(choices, cancelChoice(choices))
 			s.dequeue()
 		}
 	case CardSelectPhase:
@@ -231,20 +307,106 @@ func (s *PhaseSystem) Update(dt float32) {
 		//nope
 	case TargetPhase:
 		//still nothing
+	case ResultPhase:
+		//ResultSystem handles it
+	case WaitPhase:
+		//whatever it's waiting on dequeues it
+	case ControlsPhase:
+		//the ControlsSystem dequeues it when it's closed
+	case BacklogPhase:
+		//so does the BacklogSystem
+	case KeypadPhase:
+		//and the KeypadSystem, once the log's caught up and it's open
+		if s.keypadLogWait && s.logDone() {
+			engo.Mailbox.Dispatch(KeypadOpenMessage{})
+			s.keypadLogWait = false
+		}
+	}
+}
+
+// choose calls the Func of the choice at idx and tells the AcceptSetMessage
+// it was picked. An idx of -1 means nothing was.
+func (s *PhaseSystem) choose(choices []Choice, idx int) {
+	if idx < 0 || idx >= len(choices) {
+		return
+	}
+	if f := choices[idx].Func; f != nil {
+		f()
+	}
+	if s.accept.Chosen != nil {
+		s.accept.Chosen(idx)
+	}
+}
+
+// pauseAll pauses every system that a phase can unpause. Only the player
+// stops moving, so NPCs can still walk around in cutscenes.
+func (s *PhaseSystem) pauseAll() {
+	for _, entity := range s.entities {
+		if mover, ok := entity.(Moveable); ok && mover.GetMoveComponent().PlayerCharacter {
+			s.move.Remove(*mover.GetBasicEntity())
+		}
+		if cur, ok := entity.(CursorAble); ok {
+			s.cursor.Remove(*cur.GetBasicEntity())
+		}
 	}
+	engo.Mailbox.Dispatch(CombatLogPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(DoorSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(InterestSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(AcceptSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(CardSelectSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(AbilitySelectSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(ItemSelectSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(TargetSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(AISystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(ResultSystemPauseMessage{
+		Pause: true,
+	})
+	engo.Mailbox.Dispatch(ControlsSystemPauseMessage{
+		Pause: true,
+	})
 }
 
 func (s *PhaseSystem) dequeue() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	if len(s.queue) > 0 {
-		s.setPhase = s.queue[0]
+		s.setPhase = s.queue[0].Phase
+		s.setFunc = s.queue[0].Func
 		s.queue = s.queue[1:]
 	} else {
 		s.setPhase = BeginingPhase
+		s.setFunc = nil
 	}
 }
 
+// menuOpen reports whether p is a menu that can't be interrupted.
+func (s *PhaseSystem) menuOpen(p Phase) bool {
+	return p == ControlsPhase || p == BacklogPhase || p == KeypadPhase
+}
+
+// pausesWorld reports whether p stops the world while it's up.
+func (s *PhaseSystem) pausesWorld(p Phase) bool {
+	return p == BacklogPhase || p == KeypadPhase
+}
+
 func (s *PhaseSystem) logDone() bool {
 	msg := &CombatLogDoneMessage{}
 	engo.Mailbox.Dispatch(msg)
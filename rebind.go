@@ -0,0 +1,215 @@
+package main
+
+import (
+	"log"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+type ControlsSystemPauseMessage struct {
+	Pause bool
+}
+
+var ControlsSystemPauseMessageType = "Controls System Pause Message"
+
+func (ControlsSystemPauseMessage) Type() string { return ControlsSystemPauseMessageType }
+
+// ControlsMenuMessage opens the controls menu. The PhaseSystem sends it on
+// the ControlsPhase.
+type ControlsMenuMessage struct{}
+
+var ControlsMenuMessageType = "Controls Menu Message"
+
+func (ControlsMenuMessage) Type() string { return ControlsMenuMessageType }
+
+// ControlsSystem is the menu for rebinding the controls. The Controls button
+// opens it while it's unpaused. Picking an action then pressing a key adds
+// that key to it, or takes it off if it already had it.
+type ControlsSystem struct {
+	Fnt           *common.Font
+	BackgroundURL string
+
+	bg   sprite
+	menu ListMenu
+
+	// waiting is the action waiting for a key, or -1.
+	waiting int
+	note    string
+
+	open, paused, skipNextFrame bool
+}
+
+func (s *ControlsSystem) New(w *ecs.World) {
+	s.waiting = -1
+
+	s.bg = sprite{BasicEntity: ecs.NewBasic()}
+	s.bg.Drawable, _ = common.LoadedSprite(s.BackgroundURL)
+	s.bg.SetShader(common.HUDShader)
+	s.bg.SetZIndex(10004)
+	s.bg.Scale = engo.Point{
+		X: engo.GameWidth() / s.bg.Drawable.Width(),
+		Y: engo.GameHeight() / s.bg.Drawable.Height(),
+	}
+	s.bg.Hidden = true
+	w.AddEntity(&s.bg)
+
+	s.menu = ListMenu{
+		Position:         engo.Point{X: 60, Y: 40},
+		RowHeight:        30,
+		Rows:             9,
+		DetailPosition:   engo.Point{X: 330, Y: 40},
+		DescriptionWidth: 280,
+		Font:             s.Fnt,
+		Scale:            engo.Point{X: 0.35, Y: 0.35},
+		Wrap:             true,
+		HUD:              true,
+		OnSelect: func(idx int) {
+			switch {
+			case idx < len(controlActions):
+				s.waiting = idx
+				s.note = "Press a key to add or remove it. Esc cancels."
+			case idx == len(controlActions):
+				CurrentBindings = DefaultBindings()
+				s.apply()
+				s.note = "Back to the default keys."
+			default:
+				s.close()
+				return
+			}
+			s.menu.ReplaceEntries(s.entries())
+		},
+		OnCancel: func() {
+			s.close()
+		},
+	}
+	s.menu.Setup(w)
+
+	engo.Mailbox.Listen(ControlsSystemPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(ControlsSystemPauseMessage)
+		if !ok {
+			return
+		}
+		if msg.Pause {
+			s.pause()
+		} else {
+			s.unpause()
+		}
+	})
+	engo.Mailbox.Listen(ControlsMenuMessageType, func(message engo.Message) {
+		_, ok := message.(ControlsMenuMessage)
+		if !ok {
+			return
+		}
+		s.show()
+	})
+}
+
+func (s *ControlsSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *ControlsSystem) Update(dt float32) {
+	if s.skipNextFrame {
+		s.skipNextFrame = false
+		return
+	}
+	if !s.open {
+		if !s.paused && button("Controls").JustPressed() {
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: ControlsPhase})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: WalkPhase})
+			engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+		}
+		return
+	}
+	if s.waiting < 0 {
+		s.menu.Update()
+		return
+	}
+
+	// Keys are only read from the keyboard, so B on the pad cancels too.
+	if engo.Input.Button(keyButton(engo.KeyEscape)).JustPressed() || padHeld["B"] && !padWasHeld["B"] {
+		s.waiting = -1
+		s.note = ""
+		s.menu.ReplaceEntries(s.entries())
+		return
+	}
+	for _, n := range keyNames {
+		if !engo.Input.Button(keyButton(n.key)).JustPressed() {
+			continue
+		}
+		s.note = ""
+		if err := CurrentBindings.Toggle(controlActions[s.waiting], n.key); err != nil {
+			s.note = err.Error() + "."
+		} else {
+			s.apply()
+		}
+		s.waiting = -1
+		s.menu.ReplaceEntries(s.entries())
+		return
+	}
+}
+
+// apply registers the new bindings and saves them.
+func (s *ControlsSystem) apply() {
+	RegisterControls()
+	if err := SaveBindings(); err != nil {
+		log.Printf("Unable to save the controls. Error was: %v", err)
+	}
+}
+
+func (s *ControlsSystem) entries() []MenuEntry {
+	var entries []MenuEntry
+	for i, action := range controlActions {
+		e := MenuEntry{
+			Label:       action + ": " + CurrentBindings.Describe(action),
+			Title:       action,
+			Description: "Press A to change its keys.",
+			Enabled:     true,
+		}
+		if i == s.menu.Selected() && s.note != "" {
+			e.Description = s.note
+		}
+		entries = append(entries, e)
+	}
+	reset := MenuEntry{
+		Label:       "Reset",
+		Title:       "Reset",
+		Description: "Go back to the default keys.",
+		Enabled:     true,
+	}
+	if s.menu.Selected() == len(controlActions) && s.note != "" {
+		reset.Description = s.note
+	}
+	return append(entries, reset, MenuEntry{
+		Label:       "Done",
+		Title:       "Done",
+		Description: "Back to the game.",
+		Enabled:     true,
+	})
+}
+
+func (s *ControlsSystem) show() {
+	s.open = true
+	s.skipNextFrame = true
+	s.waiting = -1
+	s.note = ""
+	s.bg.Hidden = false
+	s.menu.SetEntries(s.entries(), s.Fnt, s.menu.Scale)
+	s.menu.Show()
+}
+
+func (s *ControlsSystem) close() {
+	s.open = false
+	s.bg.Hidden = true
+	s.menu.Hide()
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+}
+
+func (s *ControlsSystem) pause() {
+	s.paused = true
+}
+
+func (s *ControlsSystem) unpause() {
+	s.paused = false
+	s.skipNextFrame = true
+}
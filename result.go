@@ -0,0 +1,265 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+var ResultSystemPauseMessageType = "ResultSystemPauseMessage"
+
+type ResultSystemPauseMessage struct {
+	Pause bool
+}
+
+func (ResultSystemPauseMessage) Type() string { return ResultSystemPauseMessageType }
+
+var downedColor = color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xFF}
+
+type resultEntity struct {
+	chara  *Character
+	baddie *Baddie
+}
+
+// ResultSystem knocks out characters that hit 0 HP, ends the fight once
+// either side is wiped out and shows how it went before heading back to the
+// overworld.
+type ResultSystem struct {
+	Fnt  *common.Font
+	Clip *common.Player
+	// Outcome is called once when the fight ends so the scene can write it to
+	// the save.
+	Outcome func(won bool)
+	// ReturnScene is switched to after the result screen.
+	ReturnScene string
+
+	entities              []resultEntity
+	paused, skipNextFrame bool
+	done, won             bool
+
+	bg, title, prompt sprite
+}
+
+func (s *ResultSystem) New(w *ecs.World) {
+	s.paused = true
+
+	s.bg = sprite{BasicEntity: ecs.NewBasic()}
+	s.bg.Drawable = common.Rectangle{}
+	s.bg.Width = 260
+	s.bg.Height = 90
+	s.bg.Color = color.RGBA{R: 0x10, G: 0x08, B: 0x10, A: 0xE0}
+	s.bg.Position = engo.Point{X: 190, Y: 110}
+	s.bg.SetShader(common.HUDShader)
+	s.bg.SetZIndex(10003)
+	s.bg.Hidden = true
+	w.AddEntity(&s.bg)
+
+	s.title = sprite{BasicEntity: ecs.NewBasic()}
+	s.title.Drawable = common.Text{
+		Text: " ",
+		Font: s.Fnt,
+	}
+	s.title.Position = engo.Point{X: 210, Y: 120}
+	s.title.Scale = engo.Point{X: 0.6, Y: 0.6}
+	s.title.SetShader(common.HUDShader)
+	s.title.SetZIndex(10005)
+	s.title.Hidden = true
+	w.AddEntity(&s.title)
+
+	s.prompt = sprite{BasicEntity: ecs.NewBasic()}
+	s.prompt.Drawable = common.Text{
+		Text: "Press A to continue",
+		Font: s.Fnt,
+	}
+	s.prompt.Position = engo.Point{X: 210, Y: 170}
+	s.prompt.Scale = engo.Point{X: 0.3, Y: 0.3}
+	s.prompt.SetShader(common.HUDShader)
+	s.prompt.SetZIndex(10005)
+	s.prompt.Hidden = true
+	w.AddEntity(&s.prompt)
+
+	engo.Mailbox.Listen(ResultSystemPauseMessageType, func(message engo.Message) {
+		msg, ok := message.(ResultSystemPauseMessage)
+		if !ok {
+			return
+		}
+		if msg.Pause {
+			s.pause()
+		} else {
+			s.unpause()
+		}
+	})
+}
+
+func (s *ResultSystem) Add(chara *Character, bad *Baddie) {
+	s.entities = append(s.entities, resultEntity{chara, bad})
+}
+
+func (s *ResultSystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(Characterable)
+	if ok {
+		s.Add(o.GetCharacter(), nil)
+	}
+	o2, ok := i.(Baddieable)
+	if ok {
+		s.Add(nil, o2.GetBaddie())
+	}
+}
+
+func (s *ResultSystem) Remove(b ecs.BasicEntity) {
+	d := -1
+	for i, e := range s.entities {
+		if e.chara != nil {
+			if e.chara.ID() == b.ID() {
+				d = i
+				break
+			}
+		} else if e.baddie != nil {
+			if e.baddie.ID() == b.ID() {
+				d = i
+				break
+			}
+		}
+	}
+	if d >= 0 {
+		s.entities = append(s.entities[:d], s.entities[d+1:]...)
+	}
+}
+
+func (s *ResultSystem) Update(dt float32) {
+	if !s.done {
+		s.check()
+		return
+	}
+	if s.skipNextFrame {
+		s.skipNextFrame = false
+		return
+	}
+	if s.paused {
+		return
+	}
+	msg := &CombatLogDoneMessage{}
+	engo.Mailbox.Dispatch(msg)
+	if !msg.Done {
+		return
+	}
+	if s.bg.Hidden {
+		s.show()
+		return
+	}
+	if button("A").JustPressed() {
+		if err := SaveGame(); err != nil {
+			log.Printf("Unable to save the game. Error was: %v", err)
+		}
+		engo.SetSceneByName(s.ReturnScene, true)
+	}
+}
+
+// check knocks out and revives characters and ends the fight when one side
+// is all out of HP.
+func (s *ResultSystem) check() {
+	charas, baddies := 0, 0
+	downed, beaten := 0, 0
+	for _, e := range s.entities {
+		if e.chara != nil {
+			charas++
+			if e.chara.HP <= 0 && !e.chara.IsKnockedOut {
+				s.knockOut(e.chara)
+			} else if e.chara.HP > 0 && e.chara.IsKnockedOut {
+				s.revive(e.chara)
+			}
+			if e.chara.IsKnockedOut {
+				downed++
+			}
+		}
+		if e.baddie != nil {
+			baddies++
+			if e.baddie.HP <= 0 {
+				beaten++
+			}
+		}
+	}
+	if baddies > 0 && beaten == baddies {
+		s.finish(true)
+	} else if charas > 0 && downed == charas {
+		s.finish(false)
+	}
+}
+
+func (s *ResultSystem) knockOut(chara *Character) {
+	chara.CancelCast()
+	chara.Effects = nil
+	chara.IsKnockedOut = true
+	chara.card.Color = downedColor
+	chara.cardText.Color = downedColor
+	engo.Mailbox.Dispatch(chara.chat(chara.Name + " got knocked out!"))
+}
+
+func (s *ResultSystem) revive(chara *Character) {
+	chara.IsKnockedOut = false
+	chara.card.Color = color.White
+	chara.cardText.Color = color.White
+	engo.Mailbox.Dispatch(chara.chat(chara.Name + " is back on their feet!"))
+}
+
+func (s *ResultSystem) finish(won bool) {
+	s.done = true
+	s.won = won
+	for _, e := range s.entities {
+		if e.chara != nil {
+			e.chara.CancelCast()
+		}
+		if e.baddie != nil {
+			e.baddie.CancelCast()
+			if won {
+				e.baddie.spr.Hidden = true
+				e.baddie.hpBar.Hidden = true
+				e.baddie.castBar.Hidden = true
+				engo.Mailbox.Dispatch(e.baddie.chat("The " + e.baddie.Name + " fades away..."))
+			}
+		}
+	}
+	if !won {
+		engo.Mailbox.Dispatch(CombatLogMessage{
+			Msg:  "Everyone's been knocked out...",
+			Fnt:  s.Fnt,
+			Clip: s.Clip,
+		})
+	}
+	if s.Outcome != nil {
+		s.Outcome(won)
+	}
+	engo.Mailbox.Dispatch(PhaseSetMessage{
+		Phase: ResultPhase,
+	})
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+}
+
+func (s *ResultSystem) show() {
+	txt := "DEFEAT..."
+	if s.won {
+		txt = "VICTORY!"
+	}
+	s.title.Drawable = common.Text{
+		Text: txt,
+		Font: s.Fnt,
+	}
+	s.bg.Hidden = false
+	s.title.Hidden = false
+	s.prompt.Hidden = false
+}
+
+func (s *ResultSystem) pause() {
+	s.paused = true
+	s.bg.Hidden = true
+	s.title.Hidden = true
+	s.prompt.Hidden = true
+}
+
+func (s *ResultSystem) unpause() {
+	s.paused = false
+	s.skipNextFrame = true
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// roomPlacement is where a room's map goes in the world.
+type roomPlacement struct {
+	Name  string
+	Start engo.Point
+}
+
+// worldLayout is every room, starting with the one the game starts in. A
+// room's map is at maps/<Name>.json.
+var worldLayout = []roomPlacement{
+	{Name: "lobby", Start: engo.Point{X: 0, Y: 0}},
+	{Name: "lab", Start: engo.Point{X: 0, Y: 500}},
+	{Name: "president", Start: engo.Point{X: 0, Y: 1000}},
+	{Name: "space", Start: engo.Point{X: 0, Y: 1500}},
+}
+
+// playaFeet is the part of the player that bumps into walls, relative to
+// their position.
+var playaFeet = engo.AABB{Min: engo.Point{X: 6, Y: 82}, Max: engo.Point{X: 42, Y: 88}}
+
+// worldRoom is a room's map and where it goes.
+type worldRoom struct {
+	roomPlacement
+	roomInfo
+
+	// nav is built the first time FindPath is used in the room.
+	nav *navGrid
+}
+
+// loadWorld loads the map of every room in worldLayout and works out where
+// their doors go.
+func loadWorld() ([]*worldRoom, error) {
+	var rooms []*worldRoom
+	for _, p := range worldLayout {
+		info, err := loadRoomInfo("maps/" + p.Name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, &worldRoom{roomPlacement: p, roomInfo: info})
+	}
+	if err := resolveDoors(rooms); err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func findRoom(rooms []*worldRoom, name string) *worldRoom {
+	for _, r := range rooms {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// roomAt is the room pt is in, in world coordinates, or nil if it's outside
+// all of them.
+func roomAt(rooms []*worldRoom, pt engo.Point) *worldRoom {
+	for _, r := range rooms {
+		if pt.X >= r.Start.X && pt.Y >= r.Start.Y && pt.X < r.Start.X+r.Size.X && pt.Y < r.Start.Y+r.Size.Y {
+			return r
+		}
+	}
+	return nil
+}
+
+// resolveDoors sets every door's TeleportTo to its spawn point in the world.
+func resolveDoors(rooms []*worldRoom) error {
+	for _, r := range rooms {
+		for i := range r.Doors {
+			d := &r.Doors[i]
+			to := findRoom(rooms, d.To)
+			if to == nil {
+				return fmt.Errorf("%v door %v goes to %q, which isn't a room", r.Name, i+1, d.To)
+			}
+			spawn, ok := to.Spawns[d.Spawn]
+			if !ok {
+				return fmt.Errorf("%v door %v goes to spawn %q, which isn't in %v", r.Name, i+1, d.Spawn, to.Name)
+			}
+			d.TeleportTo = engo.Point{X: to.Start.X + spawn.X, Y: to.Start.Y + spawn.Y}
+		}
+	}
+	return nil
+}
+
+// checkWorld looks for doors that leave the player outside the room they go
+// to or stuck in a wall, and rooms the first room can't get to.
+func checkWorld(rooms []*worldRoom) []string {
+	var problems []string
+	for _, r := range rooms {
+		for i, d := range r.Doors {
+			to := findRoom(rooms, d.To)
+			spawn := to.Spawns[d.Spawn]
+			feet := engo.AABB{
+				Min: engo.Point{X: spawn.X + playaFeet.Min.X, Y: spawn.Y + playaFeet.Min.Y},
+				Max: engo.Point{X: spawn.X + playaFeet.Max.X, Y: spawn.Y + playaFeet.Max.Y},
+			}
+			if feet.Min.X < 0 || feet.Min.Y < 0 || feet.Max.X > to.Size.X || feet.Max.Y > to.Size.Y {
+				problems = append(problems, fmt.Sprintf("%v door %v leaves the player outside %v at %q", r.Name, i+1, to.Name, d.Spawn))
+			}
+			for j, w := range to.Walls {
+				if wallOverlaps(w, feet) {
+					problems = append(problems, fmt.Sprintf("%v door %v leaves the player in %v wall %v at %q", r.Name, i+1, to.Name, j+1, d.Spawn))
+				}
+			}
+		}
+	}
+
+	if len(rooms) == 0 {
+		return problems
+	}
+	reached := map[string]bool{rooms[0].Name: true}
+	next := []*worldRoom{rooms[0]}
+	for len(next) > 0 {
+		r := next[0]
+		next = next[1:]
+		for _, d := range r.Doors {
+			if !reached[d.To] {
+				reached[d.To] = true
+				next = append(next, findRoom(rooms, d.To))
+			}
+		}
+	}
+	for _, r := range rooms {
+		if !reached[r.Name] {
+			problems = append(problems, fmt.Sprintf("%v can't be reached from %v", r.Name, rooms[0].Name))
+		}
+	}
+	return problems
+}
+
+// runRoomCheck loads and checks every room, printing what's wrong. It
+// returns the exit code for the check-rooms flag.
+func runRoomCheck() int {
+	rooms, err := loadWorld()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	problems := checkWorld(rooms)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	fmt.Printf("All %v rooms are fine.\n", len(rooms))
+	return 0
+}
+
+// wallOverlaps reports whether box, in room coordinates, overlaps w.
+func wallOverlaps(w wallInfo, box engo.AABB) bool {
+	box = engo.AABB{
+		Min: engo.Point{X: box.Min.X - w.Position.X, Y: box.Min.Y - w.Position.Y},
+		Max: engo.Point{X: box.Max.X - w.Position.X, Y: box.Max.Y - w.Position.Y},
+	}
+	if len(w.Shapes) == 0 {
+		return box.Min.X < w.Width && box.Max.X > 0 && box.Min.Y < w.Height && box.Max.Y > 0
+	}
+	for _, s := range w.Shapes {
+		if shapeOverlaps(s, box) {
+			return true
+		}
+	}
+	return false
+}
+
+func shapeOverlaps(s common.Shape, box engo.AABB) bool {
+	if len(s.Lines) == 0 {
+		e := s.Ellipse
+		if e.Rx <= 0 || e.Ry <= 0 {
+			return false
+		}
+		// The closest point in the box to the middle of the ellipse.
+		dx := (clampFloat(e.Cx, box.Min.X, box.Max.X) - e.Cx) / e.Rx
+		dy := (clampFloat(e.Cy, box.Min.Y, box.Max.Y) - e.Cy) / e.Ry
+		return dx*dx+dy*dy < 1
+	}
+	edges := rectLines(box)
+	for _, l := range s.Lines {
+		if pointInBox(l.P1, box) {
+			return true
+		}
+		for _, e := range edges {
+			if linesCross(l, e) {
+				return true
+			}
+		}
+	}
+	closed := s.Lines[0].P1 == s.Lines[len(s.Lines)-1].P2
+	return closed && pointInLines(box.Min, s.Lines)
+}
+
+func clampFloat(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func pointInBox(p engo.Point, box engo.AABB) bool {
+	return p.X > box.Min.X && p.X < box.Max.X && p.Y > box.Min.Y && p.Y < box.Max.Y
+}
+
+// linesCross reports whether a and b cross each other, not counting just
+// touching.
+func linesCross(a, b engo.Line) bool {
+	side := func(p, q, r engo.Point) float32 {
+		return (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+	}
+	d1 := side(b.P1, b.P2, a.P1)
+	d2 := side(b.P1, b.P2, a.P2)
+	d3 := side(a.P1, a.P2, b.P1)
+	d4 := side(a.P1, a.P2, b.P2)
+	return d1*d2 < 0 && d3*d4 < 0
+}
+
+// pointInLines reports whether p is inside the polygon lines make, by
+// counting how many of them a ray to the right of p crosses.
+func pointInLines(p engo.Point, lines []engo.Line) bool {
+	in := false
+	for _, l := range lines {
+		if (l.P1.Y > p.Y) != (l.P2.Y > p.Y) {
+			x := l.P1.X + (p.Y-l.P1.Y)*(l.P2.X-l.P1.X)/(l.P2.Y-l.P1.Y)
+			if p.X < x {
+				in = !in
+			}
+		}
+	}
+	return in
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// loadTestWorld loads every room in worldLayout straight from assets/maps,
+// the way loadWorld does from the bindata.
+func loadTestWorld(t *testing.T) []*worldRoom {
+	t.Helper()
+	var rooms []*worldRoom
+	for _, p := range worldLayout {
+		data, err := ioutil.ReadFile("assets/maps/" + p.Name + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := parseTiledRoom(data, "maps")
+		if err != nil {
+			t.Fatalf("%v: %v", p.Name, err)
+		}
+		rooms = append(rooms, &worldRoom{roomPlacement: p, roomInfo: info})
+	}
+	if err := resolveDoors(rooms); err != nil {
+		t.Fatal(err)
+	}
+	return rooms
+}
+
+func box(x1, y1, x2, y2 float32) engo.AABB {
+	return engo.AABB{Min: engo.Point{X: x1, Y: y1}, Max: engo.Point{X: x2, Y: y2}}
+}
+
+// polyline joins up pts with lines, and back to the first one if closed.
+func polyline(closed bool, pts ...engo.Point) []engo.Line {
+	var lines []engo.Line
+	for i := 1; i < len(pts); i++ {
+		lines = append(lines, engo.Line{P1: pts[i-1], P2: pts[i]})
+	}
+	if closed {
+		lines = append(lines, engo.Line{P1: pts[len(pts)-1], P2: pts[0]})
+	}
+	return lines
+}
+
+func TestWallOverlaps(t *testing.T) {
+	rect := wallInfo{Position: engo.Point{X: 100, Y: 100}, Width: 50, Height: 20}
+	ellipse := wallInfo{Shapes: []common.Shape{{Ellipse: common.Ellipse{Cx: 50, Cy: 50, Rx: 40, Ry: 20}}}}
+	flat := wallInfo{Shapes: []common.Shape{{Ellipse: common.Ellipse{Cx: 50, Cy: 50, Rx: 40}}}}
+	corners := []engo.Point{{X: 0, Y: 0}, {X: 50, Y: 0}, {X: 50, Y: 50}}
+	triangle := wallInfo{
+		Position: engo.Point{X: 100, Y: 100},
+		Shapes:   []common.Shape{{Lines: polyline(true, corners...)}},
+	}
+	open := wallInfo{
+		Position: engo.Point{X: 100, Y: 100},
+		Shapes:   []common.Shape{{Lines: polyline(false, corners...)}},
+	}
+	for _, tt := range []struct {
+		name string
+		wall wallInfo
+		box  engo.AABB
+		want bool
+	}{
+		{"inside a rectangle", rect, box(120, 110, 130, 115), true},
+		{"over a rectangle's corner", rect, box(90, 90, 101, 101), true},
+		{"around a rectangle", rect, box(90, 90, 160, 130), true},
+		{"touching a rectangle", rect, box(150, 100, 160, 120), false},
+		{"away from a rectangle", rect, box(0, 0, 10, 10), false},
+
+		{"over the middle of an ellipse", ellipse, box(45, 45, 55, 55), true},
+		{"over the edge of an ellipse", ellipse, box(85, 50, 95, 60), true},
+		{"touching an ellipse", ellipse, box(90, 40, 100, 60), false},
+		{"in an ellipse's corner", ellipse, box(85, 65, 95, 75), false},
+		{"flat ellipse", flat, box(45, 45, 55, 55), false},
+
+		{"over a polygon's edge", triangle, box(140, 95, 145, 105), true},
+		{"over a polygon's corner", triangle, box(145, 145, 155, 155), true},
+		{"inside a polygon", triangle, box(135, 105, 145, 112), true},
+		{"beside a polygon", triangle, box(105, 130, 115, 140), false},
+		{"inside an open polyline", open, box(135, 105, 145, 112), false},
+		{"over a polyline", open, box(145, 120, 155, 130), true},
+	} {
+		if got := wallOverlaps(tt.wall, tt.box); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinesCross(t *testing.T) {
+	line := func(x1, y1, x2, y2 float32) engo.Line {
+		return engo.Line{P1: engo.Point{X: x1, Y: y1}, P2: engo.Point{X: x2, Y: y2}}
+	}
+	for _, tt := range []struct {
+		name string
+		a, b engo.Line
+		want bool
+	}{
+		{"crossing", line(0, 0, 10, 10), line(0, 10, 10, 0), true},
+		{"parallel", line(0, 0, 10, 0), line(0, 5, 10, 5), false},
+		{"on top of each other", line(0, 0, 10, 0), line(2, 0, 8, 0), false},
+		{"sharing an end", line(0, 0, 10, 0), line(10, 0, 10, 10), false},
+		{"touching the middle", line(0, 0, 10, 0), line(5, 0, 5, 10), false},
+		{"short of each other", line(0, 0, 4, 4), line(0, 10, 10, 0), false},
+	} {
+		if got := linesCross(tt.a, tt.b); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, got, tt.want)
+		}
+		if got := linesCross(tt.b, tt.a); got != tt.want {
+			t.Errorf("%v swapped: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPointInLines(t *testing.T) {
+	// A U shape, open at the top between x 10 and 20.
+	u := polyline(true,
+		engo.Point{X: 0, Y: 0}, engo.Point{X: 10, Y: 0}, engo.Point{X: 10, Y: 20},
+		engo.Point{X: 20, Y: 20}, engo.Point{X: 20, Y: 0}, engo.Point{X: 30, Y: 0},
+		engo.Point{X: 30, Y: 30}, engo.Point{X: 0, Y: 30},
+	)
+	for _, tt := range []struct {
+		name string
+		p    engo.Point
+		want bool
+	}{
+		{"left arm", engo.Point{X: 5, Y: 5}, true},
+		{"right arm", engo.Point{X: 25, Y: 5}, true},
+		{"bottom", engo.Point{X: 15, Y: 25}, true},
+		{"in the gap", engo.Point{X: 15, Y: 5}, false},
+		{"outside", engo.Point{X: 40, Y: 5}, false},
+		{"above", engo.Point{X: 5, Y: -5}, false},
+	} {
+		if got := pointInLines(tt.p, u); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWorld(t *testing.T) {
+	if problems := checkWorld(loadTestWorld(t)); len(problems) > 0 {
+		t.Errorf("the rooms have problems:\n%v", strings.Join(problems, "\n"))
+	}
+
+	for _, tt := range []struct {
+		name  string
+		spoil func(rooms []*worldRoom)
+		want  string
+	}{
+		{
+			name: "spawn outside the room",
+			spoil: func(rooms []*worldRoom) {
+				d := rooms[0].Doors[0]
+				findRoom(rooms, d.To).Spawns[d.Spawn] = engo.Point{X: -100, Y: -100}
+			},
+			want: "leaves the player outside",
+		},
+		{
+			name: "spawn in a wall",
+			spoil: func(rooms []*worldRoom) {
+				d := rooms[0].Doors[0]
+				to := findRoom(rooms, d.To)
+				to.Walls = append(to.Walls, wallInfo{Position: to.Spawns[d.Spawn], Width: 100, Height: 100})
+			},
+			want: "leaves the player in",
+		},
+		{
+			name: "room without a way in",
+			spoil: func(rooms []*worldRoom) {
+				for _, r := range rooms {
+					r.Doors = nil
+				}
+			},
+			want: "can't be reached from lobby",
+		},
+	} {
+		rooms := loadTestWorld(t)
+		tt.spoil(rooms)
+		problems := checkWorld(rooms)
+		found := false
+		for _, p := range problems {
+			found = found || strings.Contains(p, tt.want)
+		}
+		if !found {
+			t.Errorf("%v: problems are %q, want one saying %q", tt.name, problems, tt.want)
+		}
+	}
+}
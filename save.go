@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/EngoEngine/engo"
+)
+
+// SaveVersion is the schema version written with every save. Bump it and add
+// an entry to saveMigrations whenever SaveData changes in a way old saves
+// can't just be decoded into.
+const SaveVersion = 2
+
+// CurrentSlot is the save slot CurrentSave was loaded from and is written to.
+var CurrentSlot = 1
+
+type saveFile struct {
+	Version int
+	Data    json.RawMessage
+}
+
+// saveMigrations upgrade the raw data of a save from the version used as the
+// key to the next version. Missing fields are fine, they get the defaults from
+// NewSaveData.
+var saveMigrations = map[int]func(data map[string]interface{}){
+	// 1 -> 2: the item counters moved into the party stash.
+	1: func(data map[string]interface{}) {
+		stash := make(map[string]interface{})
+		for key, id := range map[string]ItemID{
+			"DrinkCount":   SportsDrinkItemID,
+			"CookieCount":  CookieItemID,
+			"BandageCount": BandageItemID,
+		} {
+			if n, ok := data[key].(float64); ok && n > 0 {
+				stash[string(id)] = n
+			}
+			delete(data, key)
+		}
+		data["Stash"] = stash
+	},
+}
+
+func NewSaveData() *SaveData {
+	return &SaveData{
+		PlayerLocation: engo.Point{X: 300, Y: 125},
+		Stash:          make(map[ItemID]int),
+	}
+}
+
+func EncodeSave(save *SaveData) ([]byte, error) {
+	data, err := json.Marshal(save)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(saveFile{
+		Version: SaveVersion,
+		Data:    data,
+	}, "", "  ")
+}
+
+func DecodeSave(b []byte) (*SaveData, error) {
+	var file saveFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+	if file.Version > SaveVersion {
+		return nil, fmt.Errorf("save version %v is newer than this game (%v)", file.Version, SaveVersion)
+	}
+	data := file.Data
+	if file.Version < SaveVersion {
+		raw := make(map[string]interface{})
+		if err := json.Unmarshal(file.Data, &raw); err != nil {
+			return nil, err
+		}
+		for v := file.Version; v < SaveVersion; v++ {
+			if migrate, ok := saveMigrations[v]; ok {
+				migrate(raw)
+			}
+		}
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+	save := NewSaveData()
+	if err := json.Unmarshal(data, save); err != nil {
+		return nil, err
+	}
+	return save, nil
+}
+
+// LoadGame reads the save in the given slot. If the slot has never been
+// written to, a fresh save is returned.
+func LoadGame(slot int) (*SaveData, error) {
+	b, err := readSlotImpl(slot)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return NewSaveData(), nil
+	}
+	return DecodeSave(b)
+}
+
+// SaveGame writes CurrentSave to CurrentSlot.
+func SaveGame() error {
+	b, err := EncodeSave(CurrentSave)
+	if err != nil {
+		return err
+	}
+	return writeSlotImpl(CurrentSlot, b)
+}
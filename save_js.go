@@ -0,0 +1,32 @@
+//go:build js
+// +build js
+
+package main
+
+import (
+	"strconv"
+	"syscall/js"
+)
+
+func readSlotImpl(slot int) ([]byte, error) {
+	return readConfigImpl("save" + strconv.Itoa(slot))
+}
+
+func writeSlotImpl(slot int, data []byte) error {
+	return writeConfigImpl("save"+strconv.Itoa(slot), data)
+}
+
+// readConfigImpl reads the item with the given name from local storage, or
+// nil if it hasn't been written yet.
+func readConfigImpl(name string) ([]byte, error) {
+	item := js.Global().Get("localStorage").Call("getItem", "skeleIntro."+name)
+	if item.IsNull() {
+		return nil, nil
+	}
+	return []byte(item.String()), nil
+}
+
+func writeConfigImpl(name string, data []byte) error {
+	js.Global().Get("localStorage").Call("setItem", "skeleIntro."+name, string(data))
+	return nil
+}
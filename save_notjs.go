@@ -0,0 +1,57 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+func configPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "skeleIntro", name+".json"), nil
+}
+
+func readSlotImpl(slot int) ([]byte, error) {
+	return readConfigImpl("save" + strconv.Itoa(slot))
+}
+
+func writeSlotImpl(slot int, data []byte) error {
+	return writeConfigImpl("save"+strconv.Itoa(slot), data)
+}
+
+// readConfigImpl reads the file with the given name from the game's config
+// directory, or nil if it hasn't been written yet.
+func readConfigImpl(name string) ([]byte, error) {
+	path, err := configPath(name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func writeConfigImpl(name string, data []byte) error {
+	path, err := configPath(name)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// write then rename so a crash mid-save can't eat the old one
+	tmp := path + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
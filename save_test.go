@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	save := &SaveData{
+		HasNaniteKey:          true,
+		NaniteKeyInSafe:       true,
+		HasHoodKey:            true,
+		HoodKeyInSafe:         true,
+		HasPPE:                true,
+		HasDeskKey:            true,
+		DeskKeyInSafe:         true,
+		IsDrawerBroken:        true,
+		NaniteBoxChecks:       3,
+		MarsChecks:            2,
+		HasSpookyBoard:        true,
+		HasSpookyBoardPointer: true,
+		HasSpaceKey:           true,
+		SpaceKeyInSafe:        true,
+		KeyCount:              4,
+		IsSafeOpen:            true,
+		RecruitedLen:          true,
+		RecruitedMe:           true,
+		PlayerLocation:        engo.Point{X: 412.5, Y: 87},
+		Stash: map[ItemID]int{
+			BandageItemID:     2,
+			SportsDrinkItemID: 1,
+			CookieItemID:      5,
+		},
+		HasMedKit:        true,
+		HasSalt:          true,
+		GhostDefeated:    true,
+		GhostFightLosses: 6,
+		SafePinMisses:    7,
+		SawIntro:         true,
+	}
+
+	// Every field is set, so a new one that's left out here shows up.
+	v := reflect.ValueOf(*save)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Errorf("%v isn't set in the test save", v.Type().Field(i).Name)
+		}
+	}
+
+	b, err := EncodeSave(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeSave(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, save) {
+		t.Errorf("decoded save is\n%+v\nwant\n%+v", got, save)
+	}
+}
+
+func TestDecodeSaveDefaults(t *testing.T) {
+	got, err := DecodeSave([]byte(`{"Version": 2, "Data": {"HasPPE": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewSaveData()
+	want.HasPPE = true
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded save is %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeSaveMigratesVersion1(t *testing.T) {
+	old := `{"Version": 1, "Data": {
+		"HasPPE": true,
+		"DrinkCount": 2,
+		"CookieCount": 0,
+		"BandageCount": 3,
+		"PlayerLocation": {"X": 10, "Y": 20}
+	}}`
+	got, err := DecodeSave([]byte(old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewSaveData()
+	want.HasPPE = true
+	want.PlayerLocation = engo.Point{X: 10, Y: 20}
+	want.Stash = map[ItemID]int{
+		SportsDrinkItemID: 2,
+		BandageItemID:     3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("migrated save is %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeSaveNewerVersion(t *testing.T) {
+	_, err := DecodeSave([]byte(`{"Version": 99, "Data": {}}`))
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("got error %v, want one about a newer save", err)
+	}
+}
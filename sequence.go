@@ -0,0 +1,313 @@
+package main
+
+import (
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// Sequence is a script of dialogue and actions that plays out on the
+// PhaseSystem's queue. Chain steps onto NewSequence and Play it:
+//
+//	take := NewSequence(selFont, logPlayer).SetFlag(&CurrentSave.HasKey, true)
+//	NewSequence(selFont, logPlayer).
+//		Ask([]string{"There's a key in the box!", "Take it?"}, take, nil).
+//		Play()
+//
+// Steps only go on the queue when the sequence gets to them, so a branch
+// picked by Ask, Choose, EnterCode or Then plays before whatever comes
+// after it.
+type Sequence struct {
+	fnt   *common.Font
+	clip  *common.Player
+	steps []sequenceStep
+	end   Phase
+}
+
+type sequenceStep struct {
+	lines        []string
+	voice        Voice
+	do           func()
+	options      []Option
+	columns      int
+	keypad       int
+	check        func(code string) bool
+	right, wrong *Sequence
+	then         func() *Sequence
+	walk         *MoveComponent
+	to           engo.Point
+	face         string
+}
+
+// NewSequence starts a sequence whose lines are said in fnt with clip
+// playing. When it's done the game goes back to the WalkPhase.
+func NewSequence(fnt *common.Font, clip *common.Player) *Sequence {
+	return &Sequence{
+		fnt:  fnt,
+		clip: clip,
+		end:  WalkPhase,
+	}
+}
+
+// Say shows lines in the log and waits for them to be read.
+func (s *Sequence) Say(lines ...string) *Sequence {
+	if len(lines) == 0 {
+		return s
+	}
+	s.steps = append(s.steps, sequenceStep{lines: lines, voice: Voice{Font: s.fnt, Clip: s.clip}})
+	return s
+}
+
+// SayAs is Say in someone else's voice.
+func (s *Sequence) SayAs(fnt *common.Font, clip *common.Player, lines ...string) *Sequence {
+	return s.SayVoice(Voice{Font: fnt, Clip: clip}, lines...)
+}
+
+// SayVoice is Say in v, showing its name and face.
+func (s *Sequence) SayVoice(v Voice, lines ...string) *Sequence {
+	if len(lines) == 0 {
+		return s
+	}
+	s.steps = append(s.steps, sequenceStep{lines: lines, voice: v})
+	return s
+}
+
+// Option is one answer in a sequence's choice box, with the sequence it
+// plays. Disabled is checked when the box opens, and can be nil.
+type Option struct {
+	Label    string
+	Then     *Sequence
+	Disabled func() bool
+	Default  bool
+	Cancel   bool
+}
+
+// Ask shows lines, then the yes/no box. The yes or no sequence plays
+// depending on the answer, and either can be nil.
+func (s *Sequence) Ask(lines []string, yes, no *Sequence) *Sequence {
+	return s.AskAs(s.fnt, s.clip, lines, yes, no)
+}
+
+// AskAs is Ask in someone else's voice.
+func (s *Sequence) AskAs(fnt *common.Font, clip *common.Player, lines []string, yes, no *Sequence) *Sequence {
+	return s.AskVoice(Voice{Font: fnt, Clip: clip}, lines, yes, no)
+}
+
+// AskVoice is Ask in v, showing its name and face.
+func (s *Sequence) AskVoice(v Voice, lines []string, yes, no *Sequence) *Sequence {
+	return s.ChooseVoice(v, lines, 0,
+		Option{Label: "yes", Then: yes, Default: true},
+		Option{Label: "no", Then: no, Cancel: true},
+	)
+}
+
+// Choose shows lines, then a box with options laid out columns wide (see
+// AcceptSetMessage), and plays the picked option's sequence. If B is
+// pressed and there's no Cancel option, it carries on without one.
+func (s *Sequence) Choose(lines []string, columns int, options ...Option) *Sequence {
+	return s.ChooseVoice(Voice{Font: s.fnt, Clip: s.clip}, lines, columns, options...)
+}
+
+// ChooseVoice is Choose in v, showing its name and face.
+func (s *Sequence) ChooseVoice(v Voice, lines []string, columns int, options ...Option) *Sequence {
+	s.steps = append(s.steps, sequenceStep{
+		lines:   lines,
+		voice:   v,
+		options: options,
+		columns: columns,
+	})
+	return s
+}
+
+// EnterCode opens the keypad for a code length digits long, then plays
+// right or wrong depending on what check says about it. Either can be nil,
+// and if the keypad's closed without a code it carries on with neither.
+func (s *Sequence) EnterCode(length int, check func(code string) bool, right, wrong *Sequence) *Sequence {
+	s.steps = append(s.steps, sequenceStep{
+		keypad: length,
+		check:  check,
+		right:  right,
+		wrong:  wrong,
+	})
+	return s
+}
+
+// Do calls f once everything before it has been read.
+func (s *Sequence) Do(f func()) *Sequence {
+	s.steps = append(s.steps, sequenceStep{do: f})
+	return s
+}
+
+// Walk has a non-player character walk to pt and face that way, and waits
+// for it to get there before carrying on.
+func (s *Sequence) Walk(who *MoveComponent, pt engo.Point, face string) *Sequence {
+	s.steps = append(s.steps, sequenceStep{walk: who, to: pt, face: face})
+	return s
+}
+
+// SetFlag sets a flag, usually one in CurrentSave, at this point in the
+// sequence.
+func (s *Sequence) SetFlag(flag *bool, value bool) *Sequence {
+	return s.Do(func() {
+		*flag = value
+	})
+}
+
+// Then plays whatever sequence next returns, deciding at that point in the
+// sequence. next can return nil to carry on without anything extra.
+func (s *Sequence) Then(next func() *Sequence) *Sequence {
+	s.steps = append(s.steps, sequenceStep{then: next})
+	return s
+}
+
+// EndIn changes the phase the game goes to once the sequence is over.
+func (s *Sequence) EndIn(p Phase) *Sequence {
+	s.end = p
+	return s
+}
+
+// Play puts the sequence on the phase queue and starts it.
+func (s *Sequence) Play() {
+	s.Queue()
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+}
+
+// Queue puts the sequence on the phase queue without starting it. Use it
+// when setting up a scene, since the PhaseSystem starts its queue itself.
+func (s *Sequence) Queue() {
+	s.queue(s.steps)
+}
+
+// queue puts steps on the phase queue up to the first branch. The rest is
+// queued once the branch is picked.
+func (s *Sequence) queue(steps []sequenceStep) {
+	for i, step := range steps {
+		rest := steps[i+1:]
+		switch {
+		case step.options != nil:
+			picked := -1
+			step := step
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					sayLines(step)
+					picked = -1
+					engo.Mailbox.Dispatch(AcceptSetMessage{
+						Choices: step.choices(),
+						Columns: step.columns,
+						Chosen: func(idx int) {
+							picked = idx
+						},
+					})
+				},
+			})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: AcceptPhase})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: LogClearPhase})
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					var branch *Sequence
+					if picked >= 0 {
+						branch = step.options[picked].Then
+					}
+					s.queue(s.splice(branch, rest))
+				},
+			})
+			return
+		case step.keypad > 0:
+			var branch *Sequence
+			step := step
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					branch = nil
+					engo.Mailbox.Dispatch(KeypadSetMessage{
+						Length: step.keypad,
+						Check:  step.check,
+						Done: func(code string, ok bool) {
+							branch = step.wrong
+							if ok {
+								branch = step.right
+							}
+						},
+					})
+				},
+			})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: KeypadPhase})
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					s.queue(s.splice(branch, rest))
+				},
+			})
+			return
+		case step.then != nil:
+			next := step.then
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					s.queue(s.splice(next(), rest))
+				},
+			})
+			return
+		case step.walk != nil:
+			step := step
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					step.walk.WalkTo(step.to, step.face, func() {
+						engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+					})
+				},
+			})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: WaitPhase})
+		case step.do != nil:
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func:  step.do,
+			})
+		default:
+			step := step
+			engo.Mailbox.Dispatch(PhaseSetMessage{
+				Phase: RunPhase,
+				Func: func() {
+					sayLines(step)
+				},
+			})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: ListenPhase})
+			engo.Mailbox.Dispatch(PhaseSetMessage{Phase: LogClearPhase})
+		}
+	}
+	engo.Mailbox.Dispatch(PhaseSetMessage{Phase: s.end})
+}
+
+// splice puts branch's steps in front of rest.
+func (s *Sequence) splice(branch *Sequence, rest []sequenceStep) []sequenceStep {
+	if branch == nil {
+		return rest
+	}
+	steps := make([]sequenceStep, 0, len(branch.steps)+len(rest))
+	steps = append(steps, branch.steps...)
+	return append(steps, rest...)
+}
+
+// choices are the step's options as they stand now.
+func (step sequenceStep) choices() []Choice {
+	choices := make([]Choice, len(step.options))
+	for i, o := range step.options {
+		choices[i] = Choice{
+			Label:   o.Label,
+			Default: o.Default,
+			Cancel:  o.Cancel,
+		}
+		if o.Disabled != nil {
+			choices[i].Disabled = o.Disabled()
+		}
+	}
+	return choices
+}
+
+func sayLines(step sequenceStep) {
+	for _, line := range step.lines {
+		engo.Mailbox.Dispatch(step.voice.logMessage(line))
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+)
+
+// StatusEffect is a temporary modifier on a character or baddie. It wears off
+// after Duration seconds or Turns finished casts, whichever comes first. Leave
+// either at 0 to ignore it.
+type StatusEffect struct {
+	Name      string
+	Icon      int
+	Duration  float32
+	Turns     int
+	ExpireMsg string
+
+	// Str, Def, Dex and Int are percent changes, so 100 doubles and -50 halves.
+	Str, Def, Dex, Int float32
+	// TickDamage is dealt every TickRate seconds. Negative heals.
+	TickDamage int
+	TickRate   float32
+	// Stun stops casting and cast bars while the effect lasts.
+	Stun bool
+	// CastSpeed multiplies how fast cast bars fill. 0 leaves them alone.
+	CastSpeed float32
+
+	elapsed, tickElapsed float32
+	turns                int
+}
+
+var GuardStatus = StatusEffect{
+	Name:      "Guard",
+	Icon:      0,
+	Duration:  10,
+	Turns:     1,
+	ExpireMsg: "%v lets their guard down.",
+	Def:       100,
+}
+
+var PoisonStatus = StatusEffect{
+	Name:       "Poison",
+	Icon:       1,
+	Duration:   10,
+	ExpireMsg:  "The poison wears off of %v.",
+	TickDamage: 4,
+	TickRate:   2,
+}
+
+var StunStatus = StatusEffect{
+	Name:      "Stun",
+	Icon:      2,
+	Duration:  3,
+	ExpireMsg: "%v shakes off the stun!",
+	Stun:      true,
+}
+
+var HasteStatus = StatusEffect{
+	Name:      "Haste",
+	Icon:      3,
+	Duration:  12,
+	ExpireMsg: "%v slows back down.",
+	CastSpeed: 1.5,
+}
+
+var StrUpStatus = StatusEffect{
+	Name:      "Str Up",
+	Icon:      4,
+	Duration:  15,
+	ExpireMsg: "%v's strength returns to normal.",
+	Str:       25,
+}
+
+var StrDownStatus = StatusEffect{
+	Name:      "Str Down",
+	Icon:      5,
+	Duration:  15,
+	ExpireMsg: "%v's strength returns to normal.",
+	Str:       -25,
+}
+
+var DefDownStatus = StatusEffect{
+	Name:      "Def Down",
+	Icon:      7,
+	Duration:  15,
+	ExpireMsg: "%v's defense returns to normal.",
+	Def:       -25,
+}
+
+type StatusEffectComponent struct {
+	Effects []StatusEffect
+}
+
+// AddStatus puts the effect on, or starts it over if it's already on.
+func (c *StatusEffectComponent) AddStatus(e StatusEffect) {
+	e.elapsed, e.tickElapsed, e.turns = 0, 0, 0
+	for i, e2 := range c.Effects {
+		if e2.Name == e.Name {
+			c.Effects[i] = e
+			return
+		}
+	}
+	c.Effects = append(c.Effects, e)
+}
+
+func (c *StatusEffectComponent) RemoveStatus(name string) {
+	for i, e := range c.Effects {
+		if e.Name == name {
+			c.Effects = append(c.Effects[:i], c.Effects[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *StatusEffectComponent) HasStatus(name string) bool {
+	for _, e := range c.Effects {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StatusEffectComponent) Stunned() bool {
+	for _, e := range c.Effects {
+		if e.Stun {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StatusEffectComponent) CastSpeed() float32 {
+	speed := float32(1)
+	for _, e := range c.Effects {
+		if e.CastSpeed > 0 {
+			speed *= e.CastSpeed
+		}
+	}
+	return speed
+}
+
+// Modify returns base with all of the stat changes applied.
+func (c *StatusEffectComponent) Modify(base StatsComponent) StatsComponent {
+	var str, def, dex, in float32
+	for _, e := range c.Effects {
+		str += e.Str
+		def += e.Def
+		dex += e.Dex
+		in += e.Int
+	}
+	base.Str = modStat(base.Str, str)
+	base.Def = modStat(base.Def, def)
+	base.Dex = modStat(base.Dex, dex)
+	base.Int = modStat(base.Int, in)
+	return base
+}
+
+func modStat(stat, percent float32) float32 {
+	stat *= 1 + percent/100
+	if stat < 0 {
+		return 0
+	}
+	return stat
+}
+
+// tickStatus runs timers and damage over time, returning the effects that
+// wore off.
+func (c *StatusEffectComponent) tickStatus(dt float32, stats *StatsComponent) []StatusEffect {
+	expired := []StatusEffect{}
+	kept := c.Effects[:0]
+	for _, e := range c.Effects {
+		e.elapsed += dt
+		if e.TickDamage != 0 && e.TickRate > 0 {
+			e.tickElapsed += dt
+			for e.tickElapsed >= e.TickRate {
+				e.tickElapsed -= e.TickRate
+				if e.TickDamage > 0 {
+					ApplyDamage(stats, e.TickDamage)
+				} else {
+					ApplyHeal(stats, -e.TickDamage)
+				}
+			}
+		}
+		if e.Duration > 0 && e.elapsed >= e.Duration {
+			expired = append(expired, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	c.Effects = kept
+	return expired
+}
+
+// endTurn counts a finished cast towards turn based effects, returning the
+// effects that wore off.
+func (c *StatusEffectComponent) endTurn() []StatusEffect {
+	expired := []StatusEffect{}
+	kept := c.Effects[:0]
+	for _, e := range c.Effects {
+		e.turns++
+		if e.Turns > 0 && e.turns >= e.Turns {
+			expired = append(expired, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	c.Effects = kept
+	return expired
+}
+
+func (e StatusEffect) expireMessage(name string) string {
+	if e.ExpireMsg == "" {
+		return e.Name + " wore off of " + name + "."
+	}
+	return fmt.Sprintf(e.ExpireMsg, name)
+}
+
+// Stats are the character's stats with status effects applied.
+func (c *Character) Stats() StatsComponent {
+	return c.Modify(c.StatsComponent)
+}
+
+// Stats are the baddie's stats with status effects applied.
+func (b *Baddie) Stats() StatsComponent {
+	return b.Modify(b.StatsComponent)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// effectNames lists the names of effects, in order.
+func effectNames(effects []StatusEffect) []string {
+	out := []string{}
+	for _, e := range effects {
+		out = append(out, e.Name)
+	}
+	return out
+}
+
+func TestAddStatus(t *testing.T) {
+	var c StatusEffectComponent
+	c.AddStatus(PoisonStatus)
+	c.AddStatus(StrUpStatus)
+	c.tickStatus(5, &StatsComponent{HP: 100, MaxHP: 100})
+
+	// Putting poison on again starts it over instead of stacking it.
+	c.AddStatus(PoisonStatus)
+	if got, want := effectNames(c.Effects), []string{"Poison", "Str Up"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("effects are %v, want %v", got, want)
+	}
+	if c.Effects[0].elapsed != 0 || c.Effects[1].elapsed != 5 {
+		t.Errorf("elapsed is %v and %v, want poison started over", c.Effects[0].elapsed, c.Effects[1].elapsed)
+	}
+
+	if !c.HasStatus("Str Up") || c.HasStatus("Stun") {
+		t.Errorf("has Str Up %v and Stun %v", c.HasStatus("Str Up"), c.HasStatus("Stun"))
+	}
+	c.RemoveStatus("Str Up")
+	c.RemoveStatus("Stun")
+	if got, want := effectNames(c.Effects), []string{"Poison"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("effects after removing are %v, want %v", got, want)
+	}
+}
+
+func TestStatusModify(t *testing.T) {
+	base := StatsComponent{HP: 30, MaxHP: 50, Str: 20, Def: 10, Dex: 8, Int: 40}
+	for _, tt := range []struct {
+		name    string
+		effects []StatusEffect
+		want    StatsComponent
+	}{
+		{"nothing", nil, base},
+		{"guard", []StatusEffect{GuardStatus}, StatsComponent{HP: 30, MaxHP: 50, Str: 20, Def: 20, Dex: 8, Int: 40}},
+		{"str up", []StatusEffect{StrUpStatus}, StatsComponent{HP: 30, MaxHP: 50, Str: 25, Def: 10, Dex: 8, Int: 40}},
+		{"str down", []StatusEffect{StrDownStatus}, StatsComponent{HP: 30, MaxHP: 50, Str: 15, Def: 10, Dex: 8, Int: 40}},
+		{"up and down cancel", []StatusEffect{StrUpStatus, StrDownStatus}, base},
+		{"guard and def down add up", []StatusEffect{GuardStatus, DefDownStatus}, StatsComponent{HP: 30, MaxHP: 50, Str: 20, Def: 17.5, Dex: 8, Int: 40}},
+		{"can't go below 0", []StatusEffect{{Dex: -150, Int: -100}}, StatsComponent{HP: 30, MaxHP: 50, Str: 20, Def: 10, Dex: 0, Int: 0}},
+		{"no stat changes", []StatusEffect{PoisonStatus, StunStatus, HasteStatus}, base},
+	} {
+		c := StatusEffectComponent{Effects: tt.effects}
+		if got := c.Modify(base); got != tt.want {
+			t.Errorf("%v: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStunAndHaste(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		effects []StatusEffect
+		stunned bool
+		speed   float32
+	}{
+		{"nothing", nil, false, 1},
+		{"stun", []StatusEffect{StunStatus}, true, 1},
+		{"haste", []StatusEffect{HasteStatus}, false, 1.5},
+		{"stunned with haste", []StatusEffect{HasteStatus, StunStatus}, true, 1.5},
+		{"two hastes", []StatusEffect{HasteStatus, {Name: "Haste 2", CastSpeed: 2}}, false, 3},
+		{"other effects", []StatusEffect{PoisonStatus, GuardStatus}, false, 1},
+	} {
+		c := StatusEffectComponent{Effects: tt.effects}
+		if got := c.Stunned(); got != tt.stunned {
+			t.Errorf("%v: stunned is %v, want %v", tt.name, got, tt.stunned)
+		}
+		if got := c.CastSpeed(); got != tt.speed {
+			t.Errorf("%v: cast speed is %v, want %v", tt.name, got, tt.speed)
+		}
+	}
+}
+
+func TestTickStatus(t *testing.T) {
+	regen := StatusEffect{Name: "Regen", Duration: 6, TickDamage: -5, TickRate: 3}
+	for _, tt := range []struct {
+		name    string
+		effect  StatusEffect
+		dts     []float32
+		hp      float32
+		expired []string
+	}{
+		{"poison before its first tick", PoisonStatus, []float32{1.9}, 50, []string{}},
+		{"poison ticks", PoisonStatus, []float32{1, 1}, 46, []string{}},
+		{"poison catches up on a long frame", PoisonStatus, []float32{6.5}, 38, []string{}},
+		{"poison wears off", PoisonStatus, []float32{4, 4, 2}, 30, []string{"Poison"}},
+		{"poison ticks on the frame it wears off", PoisonStatus, []float32{10}, 30, []string{"Poison"}},
+		{"heals over time", regen, []float32{3}, 55, []string{}},
+		{"heals up to max", regen, []float32{3, 3}, 60, []string{"Regen"}},
+		{"timer runs out", StunStatus, []float32{1, 1, 1}, 50, []string{"Stun"}},
+		{"timer left", StunStatus, []float32{1, 1}, 50, []string{}},
+		{"no duration lasts forever", StatusEffect{Name: "Forever", Turns: 1}, []float32{1000}, 50, []string{}},
+	} {
+		c := StatusEffectComponent{}
+		c.AddStatus(tt.effect)
+		stats := StatsComponent{HP: 50, MaxHP: 60}
+		expired := []StatusEffect{}
+		for _, dt := range tt.dts {
+			expired = append(expired, c.tickStatus(dt, &stats)...)
+		}
+		if stats.HP != tt.hp {
+			t.Errorf("%v: HP is %v, want %v", tt.name, stats.HP, tt.hp)
+		}
+		if got := effectNames(expired); !reflect.DeepEqual(got, tt.expired) {
+			t.Errorf("%v: expired %v, want %v", tt.name, got, tt.expired)
+		}
+		if wore := len(tt.expired) > 0; c.HasStatus(tt.effect.Name) == wore {
+			t.Errorf("%v: still on is %v after wearing off is %v", tt.name, c.HasStatus(tt.effect.Name), wore)
+		}
+	}
+
+	// Poison doesn't take HP below 0.
+	c := StatusEffectComponent{}
+	c.AddStatus(PoisonStatus)
+	stats := StatsComponent{HP: 3, MaxHP: 60}
+	c.tickStatus(2, &stats)
+	if stats.HP != 0 {
+		t.Errorf("poisoned down to %v HP, want 0", stats.HP)
+	}
+}
+
+func TestEndTurn(t *testing.T) {
+	var c StatusEffectComponent
+	c.AddStatus(GuardStatus)
+	c.AddStatus(StatusEffect{Name: "Two Turns", Turns: 2})
+	c.AddStatus(HasteStatus)
+
+	if got, want := effectNames(c.endTurn()), []string{"Guard"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first turn expired %v, want %v", got, want)
+	}
+	if got, want := effectNames(c.endTurn()), []string{"Two Turns"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second turn expired %v, want %v", got, want)
+	}
+	// Haste only goes by time.
+	for i := 0; i < 10; i++ {
+		c.endTurn()
+	}
+	if got, want := effectNames(c.Effects), []string{"Haste"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("effects left are %v, want %v", got, want)
+	}
+
+	// Guard wears off on time too if the turn never comes.
+	c = StatusEffectComponent{}
+	c.AddStatus(GuardStatus)
+	if got, want := effectNames(c.tickStatus(GuardStatus.Duration, &StatsComponent{})), []string{"Guard"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("guard timed out %v, want %v", got, want)
+	}
+}
+
+func TestExpireMessage(t *testing.T) {
+	if got, want := PoisonStatus.expireMessage("Len"), "The poison wears off of Len."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := (StatusEffect{Name: "Gloom"}).expireMessage("Len"), "Gloom wore off of Len."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
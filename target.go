@@ -1,8 +1,11 @@
 package main
 
 import (
+	"image/color"
+
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
 )
 
 var TargetSystemPauseMessageType = "TargetSystemPauseMessage"
@@ -13,17 +16,53 @@ type TargetSystemPauseMessage struct {
 
 func (TargetSystemPauseMessage) Type() string { return TargetSystemPauseMessageType }
 
+var targetHighlight = color.RGBA{R: 0xFF, G: 0xFF, B: 0x80, A: 0xFF}
+
 type targetEntity struct {
 	chara  *Character
 	baddie *Baddie
 }
 
+func (e targetEntity) render() *common.RenderComponent {
+	if e.chara != nil {
+		return &e.chara.card.RenderComponent
+	}
+	return &e.baddie.spr.RenderComponent
+}
+
+// rest is the color the target goes back to when it isn't highlighted.
+func (e targetEntity) rest() color.Color {
+	if e.chara != nil && e.chara.IsKnockedOut {
+		return downedColor
+	}
+	return color.White
+}
+
+func (e targetEntity) space() *common.SpaceComponent {
+	if e.chara != nil {
+		return &e.chara.card.SpaceComponent
+	}
+	return &e.baddie.spr.SpaceComponent
+}
+
 type TargetSystem struct {
 	entities              []targetEntity
 	paused, skipNextFrame bool
+	cursor                sprite
+	candidates            []targetEntity
+	curIdx, setIdx        int
 }
 
 func (s *TargetSystem) New(w *ecs.World) {
+	curTex, _ := common.LoadedSprite("title/cursor.png")
+	s.cursor = sprite{BasicEntity: ecs.NewBasic()}
+	s.cursor.Drawable = curTex
+	s.cursor.Width = curTex.Width()
+	s.cursor.Height = curTex.Height()
+	s.cursor.SetZIndex(3)
+	s.cursor.Hidden = true
+	w.AddEntity(&s.cursor)
+
 	engo.Mailbox.Listen(TargetSystemPauseMessageType, func(message engo.Message) {
 		msg, ok := message.(TargetSystemPauseMessage)
 		if !ok {
@@ -57,7 +96,7 @@ func (s *TargetSystem) Remove(b ecs.BasicEntity) {
 	for i, e := range s.entities {
 		if e.chara != nil {
 			if e.chara.ID() == b.ID() {
-				d = 1
+				d = i
 				break
 			}
 		}
@@ -82,43 +121,189 @@ func (s *TargetSystem) Update(dt float32) {
 		return
 	}
 
-	var chara *Character
-	for _, e := range s.entities {
-		if e.chara != nil {
-			if e.chara.IsCardSelected {
-				chara = e.chara
-				break
-			}
-		}
-	}
+	chara := s.selected()
 	if chara == nil {
 		return
 	}
+	var targetType Target
+	var back Phase
 	if chara.IsItemSelected {
-		switch chara.SelectedItem.TargetType {
-		case TargetTypeAll:
-			
-		case TargetTypeAllEnemy:
+		targetType = chara.SelectedItem.TargetType
+		back = ItemSelectPhase
+	} else if chara.IsAbilitySelected {
+		targetType = chara.SelectedAbility.TargetType
+		back = AbilitySelectPhase
+	} else if chara.SelectedAbility.EffectFunc != nil {
+		// quick actions straight from the card select
+		targetType = chara.SelectedAbility.TargetType
+		back = CardSelectPhase
+	} else {
+		return
+	}
 
-		case TargetTypeAllFriend:
+	switch targetType {
+	case TargetTypeNone:
+		s.startCast(chara, []*Character{}, []*Baddie{})
+		return
+	case TargetTypeAll:
+		s.startCast(chara, s.characters(), s.baddies())
+		return
+	case TargetTypeAllEnemy:
+		s.startCast(chara, []*Character{}, s.baddies())
+		return
+	case TargetTypeAllFriend:
+		s.startCast(chara, s.characters(), []*Baddie{})
+		return
+	case TargetTypeSingleAny, TargetTypeSingleEnemy, TargetTypeSingleFriend:
+		if s.candidates == nil {
+			s.setCandidates(targetType)
+		}
+	}
 
-		case TargetTypeSingleAny:
+	if button("B").JustPressed() {
+		chara.IsItemSelected = false
+		chara.IsAbilitySelected = false
+		if back == CardSelectPhase {
+			chara.SelectedAbility = Ability{}
+		}
+		engo.Mailbox.Dispatch(PhaseSetMessage{
+			Phase: back,
+		})
+		engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+		return
+	}
+	if len(s.candidates) == 0 {
+		return
+	}
+	if button("left").JustPressed() || button("up").JustPressed() {
+		s.curIdx--
+		if s.curIdx < 0 {
+			s.curIdx = len(s.candidates) - 1
+		}
+	} else if button("right").JustPressed() || button("down").JustPressed() {
+		s.curIdx++
+		if s.curIdx >= len(s.candidates) {
+			s.curIdx = 0
+		}
+	} else if button("A").JustPressed() {
+		tar := s.candidates[s.curIdx]
+		if tar.chara != nil {
+			s.startCast(chara, []*Character{tar.chara}, []*Baddie{})
+		} else {
+			s.startCast(chara, []*Character{}, []*Baddie{tar.baddie})
+		}
+		return
+	}
+	if s.curIdx != s.setIdx {
+		s.highlight()
+	}
+}
 
-		case TargetTypeSingleEnemy:
+func (s *TargetSystem) selected() *Character {
+	for _, e := range s.entities {
+		if e.chara != nil {
+			if e.chara.IsCardSelected {
+				return e.chara
+			}
+		}
+	}
+	return nil
+}
 
-		case TargetTypeSingleFriend:
+func (s *TargetSystem) characters() []*Character {
+	ret := make([]*Character, 0)
+	for _, e := range s.entities {
+		if e.chara != nil {
+			ret = append(ret, e.chara)
+		}
+	}
+	return ret
+}
 
+func (s *TargetSystem) baddies() []*Baddie {
+	ret := make([]*Baddie, 0)
+	for _, e := range s.entities {
+		if e.baddie != nil && e.baddie.HP > 0 {
+			ret = append(ret, e.baddie)
 		}
-	} else if chara.IsAbilitySelected {
+	}
+	return ret
+}
 
+func (s *TargetSystem) setCandidates(t Target) {
+	s.candidates = make([]targetEntity, 0)
+	if t == TargetTypeSingleFriend || t == TargetTypeSingleAny {
+		for _, c := range s.characters() {
+			s.candidates = append(s.candidates, targetEntity{chara: c})
+		}
 	}
+	if t == TargetTypeSingleEnemy || t == TargetTypeSingleAny {
+		for _, b := range s.baddies() {
+			s.candidates = append(s.candidates, targetEntity{baddie: b})
+		}
+	}
+	s.curIdx = 0
+	s.setIdx = -1
+}
+
+func (s *TargetSystem) highlight() {
+	for i, c := range s.candidates {
+		if i == s.curIdx {
+			c.render().Color = targetHighlight
+		} else {
+			c.render().Color = c.rest()
+		}
+	}
+	space := s.candidates[s.curIdx].space()
+	s.cursor.Position = engo.Point{
+		X: space.Position.X - s.cursor.Width - 2,
+		Y: space.Position.Y + (space.Height-s.cursor.Height)/2,
+	}
+	s.cursor.Hidden = false
+	s.setIdx = s.curIdx
+}
+
+// startCast hands the chosen targets over to the BarSystem and goes back to
+// picking cards while the cast bar fills up.
+func (s *TargetSystem) startCast(chara *Character, players []*Character, baddies []*Baddie) {
+	chara.TargetPlayers = players
+	chara.TargetBaddies = baddies
+	engo.Mailbox.Dispatch(CastStartMessage{Chara: chara})
+	engo.Mailbox.Dispatch(PhaseSetMessage{
+		Phase: CardSelectPhase,
+	})
+	engo.Mailbox.Dispatch(PhaseDequeuMessage{})
+	s.pause()
 }
 
 func (s *TargetSystem) pause() {
 	s.paused = true
+	s.cursor.Hidden = true
+	for _, c := range s.candidates {
+		c.render().Color = c.rest()
+	}
+	s.candidates = nil
+	for _, e := range s.entities {
+		if e.chara != nil {
+			e.chara.card.Hidden = true
+			e.chara.cardText.Hidden = true
+			e.chara.hpBar.Hidden = true
+			e.chara.mpBar.Hidden = true
+			e.chara.castBar.Hidden = true
+		}
+	}
 }
 
 func (s *TargetSystem) unpause() {
 	s.paused = false
 	s.skipNextFrame = true
+	for _, e := range s.entities {
+		if e.chara != nil {
+			e.chara.card.Hidden = false
+			e.chara.cardText.Hidden = false
+			e.chara.hpBar.Hidden = false
+			e.chara.mpBar.Hidden = false
+			e.chara.castBar.Hidden = false
+		}
+	}
 }
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+
+	"github.com/SkeleboyStudios/skeleIntro/assets"
+)
+
+// roomInfo is what newRoom needs to build a room, along with the room's
+// size and named spawn points for the room check.
+type roomInfo struct {
+	Background string
+	Size       engo.Point
+	Walls      []wallInfo
+	Doors      []doorInfo
+	Interests  []interestInfo
+	Spawns     map[string]engo.Point
+}
+
+// tiledMap is the part of a map saved in Tiled's JSON format that rooms use.
+// The background is the map's first image layer, and the "walls", "doors",
+// "interests" and "spawns" object layers hold the rest.
+type tiledMap struct {
+	Width      int          `json:"width"`
+	Height     int          `json:"height"`
+	TileWidth  int          `json:"tilewidth"`
+	TileHeight int          `json:"tileheight"`
+	Layers     []tiledLayer `json:"layers"`
+}
+
+type tiledLayer struct {
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	Image   string        `json:"image"`
+	Objects []tiledObject `json:"objects"`
+	// Layers is set for group layers.
+	Layers []tiledLayer `json:"layers"`
+}
+
+// tiledObject is a rectangle, ellipse, point, polygon or polyline. The
+// points of polygons and polylines are relative to X and Y.
+type tiledObject struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	X          float32         `json:"x"`
+	Y          float32         `json:"y"`
+	Width      float32         `json:"width"`
+	Height     float32         `json:"height"`
+	Ellipse    bool            `json:"ellipse"`
+	Polygon    []engo.Point    `json:"polygon"`
+	Polyline   []engo.Point    `json:"polyline"`
+	Properties []tiledProperty `json:"properties"`
+}
+
+type tiledProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// loadRoomInfo reads the Tiled map at url from the asset bundle.
+func loadRoomInfo(url string) (roomInfo, error) {
+	data, err := assets.Asset(url)
+	if err != nil {
+		return roomInfo{}, err
+	}
+	info, err := parseTiledRoom(data, path.Dir(url))
+	if err != nil {
+		return roomInfo{}, fmt.Errorf("%v: %v", url, err)
+	}
+	return info, nil
+}
+
+// parseTiledRoom reads a Tiled JSON map. Image paths in a map are relative to
+// it, so dir is where the map is in the asset bundle.
+func parseTiledRoom(data []byte, dir string) (roomInfo, error) {
+	m := tiledMap{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return roomInfo{}, err
+	}
+	info := roomInfo{
+		Size: engo.Point{
+			X: float32(m.Width * m.TileWidth),
+			Y: float32(m.Height * m.TileHeight),
+		},
+		Spawns: make(map[string]engo.Point),
+	}
+	if err := info.addLayers(m.Layers, dir); err != nil {
+		return roomInfo{}, err
+	}
+	if info.Background == "" {
+		return roomInfo{}, errors.New("the map needs an image layer for the background")
+	}
+	return info, nil
+}
+
+func (info *roomInfo) addLayers(layers []tiledLayer, dir string) error {
+	for _, l := range layers {
+		switch l.Type {
+		case "group":
+			if err := info.addLayers(l.Layers, dir); err != nil {
+				return err
+			}
+		case "imagelayer":
+			if info.Background == "" {
+				info.Background = path.Join(dir, l.Image)
+			}
+		case "objectgroup":
+			for _, o := range l.Objects {
+				if err := info.addObject(l.Name, o, dir); err != nil {
+					return fmt.Errorf("%v object %v: %v", l.Name, o.label(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (info *roomInfo) addObject(layer string, o tiledObject, dir string) error {
+	pos := engo.Point{X: o.X, Y: o.Y}
+	switch layer {
+	case "walls":
+		w := wallInfo{Position: pos}
+		if shape, ok := o.shape(); ok {
+			w.Shapes = []common.Shape{shape}
+		} else {
+			w.Width, w.Height = o.Width, o.Height
+		}
+		info.Walls = append(info.Walls, w)
+	case "doors":
+		d := doorInfo{Position: pos, Shapes: []common.Shape{o.collisionShape()}}
+		var err error
+		if d.URL, err = o.fileProperty("image", dir); err != nil {
+			return err
+		}
+		if d.CellWidth, err = o.intProperty("cellWidth"); err != nil {
+			return err
+		}
+		if d.CellHeight, err = o.intProperty("cellHeight"); err != nil {
+			return err
+		}
+		// Borders are usually 1 pixel, so they're optional.
+		d.BorderWidth, d.BorderHeight = 1, 1
+		if _, ok := o.property("borderWidth"); ok {
+			if d.BorderWidth, err = o.intProperty("borderWidth"); err != nil {
+				return err
+			}
+		}
+		if _, ok := o.property("borderHeight"); ok {
+			if d.BorderHeight, err = o.intProperty("borderHeight"); err != nil {
+				return err
+			}
+		}
+		if d.OpenFrames, err = o.framesProperty("openFrames"); err != nil {
+			return err
+		}
+		if d.CloseFrames, err = o.framesProperty("closeFrames"); err != nil {
+			return err
+		}
+		if d.Button, err = o.stringProperty("button"); err != nil {
+			return err
+		}
+		if d.To, err = o.stringProperty("to"); err != nil {
+			return err
+		}
+		if d.Spawn, err = o.stringProperty("spawn"); err != nil {
+			return err
+		}
+		info.Doors = append(info.Doors, d)
+	case "interests":
+		i := interestInfo{Position: pos, Shapes: []common.Shape{o.collisionShape()}}
+		var err error
+		if i.URL, err = o.fileProperty("image", dir); err != nil {
+			return err
+		}
+		if i.Script, err = o.stringProperty("script"); err != nil {
+			return err
+		}
+		info.Interests = append(info.Interests, i)
+	case "spawns":
+		if o.Name == "" {
+			return errors.New("spawn points need a name")
+		}
+		if _, ok := info.Spawns[o.Name]; ok {
+			return errors.New("there's already a spawn point with that name")
+		}
+		info.Spawns[o.Name] = pos
+	}
+	return nil
+}
+
+func (o tiledObject) label() string {
+	if o.Name != "" {
+		return strconv.Quote(o.Name)
+	}
+	return strconv.Itoa(o.ID)
+}
+
+// shape is the object's collision shape, or false for a plain rectangle.
+func (o tiledObject) shape() (common.Shape, bool) {
+	switch {
+	case o.Ellipse:
+		return common.Shape{Ellipse: common.Ellipse{
+			Cx: o.Width / 2,
+			Cy: o.Height / 2,
+			Rx: o.Width / 2,
+			Ry: o.Height / 2,
+		}}, true
+	case o.Polygon != nil:
+		return common.Shape{Lines: pointLines(o.Polygon, true)}, true
+	case o.Polyline != nil:
+		return common.Shape{Lines: pointLines(o.Polyline, false)}, true
+	}
+	return common.Shape{}, false
+}
+
+// collisionShape is like shape, but turns rectangles into lines too for
+// things that don't collide with their size.
+func (o tiledObject) collisionShape() common.Shape {
+	if shape, ok := o.shape(); ok {
+		return shape
+	}
+	return common.Shape{Lines: rectLines(engo.AABB{Max: engo.Point{X: o.Width, Y: o.Height}})}
+}
+
+// rectLines is the outline of r.
+func rectLines(r engo.AABB) []engo.Line {
+	return pointLines([]engo.Point{
+		r.Min,
+		{X: r.Max.X, Y: r.Min.Y},
+		r.Max,
+		{X: r.Min.X, Y: r.Max.Y},
+	}, true)
+}
+
+// pointLines joins up points with lines, and the last one back to the first
+// if closed.
+func pointLines(points []engo.Point, closed bool) []engo.Line {
+	var lines []engo.Line
+	for i := 0; i+1 < len(points); i++ {
+		lines = append(lines, engo.Line{P1: points[i], P2: points[i+1]})
+	}
+	if closed && len(points) > 2 {
+		lines = append(lines, engo.Line{P1: points[len(points)-1], P2: points[0]})
+	}
+	return lines
+}
+
+func (o tiledObject) property(name string) (interface{}, bool) {
+	for _, p := range o.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o tiledObject) stringProperty(name string) (string, error) {
+	v, ok := o.property(name)
+	if !ok {
+		return "", fmt.Errorf("missing the %q property", name)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("the %q property should be a string", name)
+	}
+	return s, nil
+}
+
+func (o tiledObject) fileProperty(name, dir string) (string, error) {
+	s, err := o.stringProperty(name)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, s), nil
+}
+
+func (o tiledObject) floatProperty(name string) (float32, error) {
+	v, ok := o.property(name)
+	if !ok {
+		return 0, fmt.Errorf("missing the %q property", name)
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("the %q property should be a number", name)
+	}
+	return float32(f), nil
+}
+
+func (o tiledObject) intProperty(name string) (int, error) {
+	f, err := o.floatProperty(name)
+	if err != nil {
+		return 0, err
+	}
+	if f != float32(int(f)) {
+		return 0, fmt.Errorf("the %q property should be a whole number", name)
+	}
+	return int(f), nil
+}
+
+// framesProperty reads animation frames written like "0,1,2,3".
+func (o tiledObject) framesProperty(name string) ([]int, error) {
+	s, err := o.stringProperty(name)
+	if err != nil {
+		return nil, err
+	}
+	var frames []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("the %q property should be frame numbers like 0,1,2", name)
+		}
+		frames = append(frames, n)
+	}
+	return frames, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+func loadSampleRoom(t *testing.T) roomInfo {
+	t.Helper()
+	data, err := ioutil.ReadFile("testdata/room.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseTiledRoom(data, "maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestParseTiledRoom(t *testing.T) {
+	info := loadSampleRoom(t)
+	if info.Background != "room/bg.png" {
+		t.Errorf("background is %q, want room/bg.png", info.Background)
+	}
+	if want := (engo.Point{X: 640, Y: 320}); info.Size != want {
+		t.Errorf("size is %v, want %v", info.Size, want)
+	}
+}
+
+func TestParseTiledWalls(t *testing.T) {
+	info := loadSampleRoom(t)
+	if len(info.Walls) != 3 {
+		t.Fatalf("got %v walls, want 3", len(info.Walls))
+	}
+
+	rect := info.Walls[0]
+	if rect.Position != (engo.Point{X: 10, Y: 20}) || rect.Width != 100 || rect.Height != 40 || rect.Shapes != nil {
+		t.Errorf("rectangle wall is %+v", rect)
+	}
+
+	ellipse := info.Walls[1]
+	want := common.Ellipse{Cx: 30, Cy: 15, Rx: 30, Ry: 15}
+	if len(ellipse.Shapes) != 1 || ellipse.Shapes[0].Ellipse != want {
+		t.Errorf("ellipse wall is %+v, want ellipse %+v", ellipse, want)
+	}
+
+	poly := info.Walls[2]
+	lines := []engo.Line{
+		{P1: engo.Point{X: 0, Y: 0}, P2: engo.Point{X: 50, Y: 0}},
+		{P1: engo.Point{X: 50, Y: 0}, P2: engo.Point{X: 50, Y: 50}},
+		{P1: engo.Point{X: 50, Y: 50}, P2: engo.Point{X: 0, Y: 0}},
+	}
+	if poly.Position != (engo.Point{X: 300, Y: 0}) || len(poly.Shapes) != 1 || !reflect.DeepEqual(poly.Shapes[0].Lines, lines) {
+		t.Errorf("polygon wall is %+v, want lines %v", poly, lines)
+	}
+}
+
+func TestParseTiledDoors(t *testing.T) {
+	info := loadSampleRoom(t)
+	if len(info.Doors) != 1 {
+		t.Fatalf("got %v doors, want 1", len(info.Doors))
+	}
+	d := info.Doors[0]
+	if d.URL != "room/door.png" || d.Position != (engo.Point{X: 400, Y: 100}) {
+		t.Errorf("door is at %v with image %q", d.Position, d.URL)
+	}
+	if d.CellWidth != 32 || d.CellHeight != 64 {
+		t.Errorf("door cells are %vx%v, want 32x64", d.CellWidth, d.CellHeight)
+	}
+	// borderWidth is set to 0 and borderHeight is left to default to 1.
+	if d.BorderWidth != 0 || d.BorderHeight != 1 {
+		t.Errorf("door borders are %v and %v, want 0 and 1", d.BorderWidth, d.BorderHeight)
+	}
+	if !reflect.DeepEqual(d.OpenFrames, []int{0, 1, 2}) || !reflect.DeepEqual(d.CloseFrames, []int{2, 1, 0}) {
+		t.Errorf("door frames are %v and %v", d.OpenFrames, d.CloseFrames)
+	}
+	if d.Button != "up" || d.To != "hall" || d.Spawn != "from-room" {
+		t.Errorf("door is %+v", d)
+	}
+	// Rectangles become lines since doors don't collide with their size.
+	if len(d.Shapes) != 1 || len(d.Shapes[0].Lines) != 4 {
+		t.Errorf("door shapes are %+v, want a 4 line rectangle", d.Shapes)
+	}
+}
+
+func TestParseTiledInterestsAndSpawns(t *testing.T) {
+	info := loadSampleRoom(t)
+	if len(info.Interests) != 1 {
+		t.Fatalf("got %v interests, want 1", len(info.Interests))
+	}
+	i := info.Interests[0]
+	if i.URL != "room/desk.png" || i.Script != "room.desk" || i.Position != (engo.Point{X: 50, Y: 150}) {
+		t.Errorf("interest is %+v", i)
+	}
+	// Polylines aren't closed.
+	if len(i.Shapes) != 1 || len(i.Shapes[0].Lines) != 2 {
+		t.Errorf("interest shapes are %+v, want a 2 line polyline", i.Shapes)
+	}
+
+	want := map[string]engo.Point{"from-hall": {X: 420, Y: 180}}
+	if !reflect.DeepEqual(info.Spawns, want) {
+		t.Errorf("spawns are %v, want %v", info.Spawns, want)
+	}
+}
+
+func TestParseTiledRoomErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, data, err string
+	}{
+		{
+			name: "no background",
+			data: `{"layers": []}`,
+			err:  "image layer",
+		},
+		{
+			name: "door without an image",
+			data: `{"layers": [{"type": "imagelayer", "image": "bg.png"},
+				{"type": "objectgroup", "name": "doors", "objects": [{"id": 7}]}]}`,
+			err: `doors object 7: missing the "image" property`,
+		},
+		{
+			name: "bad frames",
+			data: `{"layers": [{"type": "imagelayer", "image": "bg.png"},
+				{"type": "objectgroup", "name": "doors", "objects": [{"id": 7, "properties": [
+					{"name": "image", "value": "door.png"},
+					{"name": "cellWidth", "value": 32},
+					{"name": "cellHeight", "value": 64},
+					{"name": "openFrames", "value": "0,one"}]}]}]}`,
+			err: "frame numbers",
+		},
+		{
+			name: "unnamed spawn",
+			data: `{"layers": [{"type": "imagelayer", "image": "bg.png"},
+				{"type": "objectgroup", "name": "spawns", "objects": [{"id": 3}]}]}`,
+			err: "need a name",
+		},
+		{
+			name: "repeated spawn",
+			data: `{"layers": [{"type": "imagelayer", "image": "bg.png"},
+				{"type": "objectgroup", "name": "spawns", "objects": [{"id": 3, "name": "a"}, {"id": 4, "name": "a"}]}]}`,
+			err: "already a spawn point",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTiledRoom([]byte(tt.data), "maps")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want one about %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseRoomMaps(t *testing.T) {
+	for _, name := range []string{"lobby", "lab", "president", "space"} {
+		data, err := ioutil.ReadFile("assets/maps/" + name + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := parseTiledRoom(data, "maps")
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		if len(info.Walls) == 0 || len(info.Doors) == 0 || len(info.Spawns) == 0 {
+			t.Errorf("%v has %v walls, %v doors and %v spawns", name, len(info.Walls), len(info.Doors), len(info.Spawns))
+		}
+	}
+}
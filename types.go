@@ -53,6 +53,15 @@ type playa struct {
 	MoveComponent
 }
 
+// npcPtr moves an entity that's already in the world, like an interest.
+type npcPtr struct {
+	*ecs.BasicEntity
+	*common.AnimationComponent
+	*common.SpaceComponent
+	*common.RenderComponent
+	*MoveComponent
+}
+
 type door struct {
 	ecs.BasicEntity
 	common.CollisionComponent
@@ -78,6 +87,7 @@ type interest struct {
 	common.CollisionComponent
 	common.RenderComponent
 	common.SpaceComponent
+	common.MouseComponent
 
 	InterestComponent
 }
@@ -105,6 +115,9 @@ type doorInfo struct {
 	Shapes                                           []common.Shape
 	OpenFrames, CloseFrames                          []int
 	Button                                           string
+	// To and Spawn are the room and spawn point the door goes to. loadWorld
+	// works out TeleportTo from them.
+	To, Spawn string
 }
 
 type wallInfo struct {
@@ -117,6 +130,7 @@ type interestInfo struct {
 	URL      string
 	Position engo.Point
 	Shapes   []common.Shape
+	Script   string
 	Func     func()
 }
 
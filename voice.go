@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// Portrait is a speaker's face, with a drawable for each expression. The ""
+// expression is used when a line doesn't pick one.
+type Portrait map[string]common.Drawable
+
+// partyFaces are the expressions in faces/party.png, a column each. Each row
+// is someone in the party: You, Me and then Len.
+var partyFaces = []string{"", "happy", "surprised", "hurt"}
+
+// partyPortrait is the portrait on the given row of faces.
+func partyPortrait(faces *common.Spritesheet, row int) Portrait {
+	p := make(Portrait)
+	for i, face := range partyFaces {
+		p[face] = faces.Drawable(row*len(partyFaces) + i)
+	}
+	return p
+}
+
+// moodFace is the face someone makes in a fight: "angry" once they're down
+// to two thirds of their HP and "hurt" once they're down to a third.
+func moodFace(stats StatsComponent) string {
+	switch {
+	case stats.HP <= stats.MaxHP/3:
+		return "hurt"
+	case stats.HP <= stats.MaxHP*2/3:
+		return "angry"
+	}
+	return ""
+}
+
+// Face is the drawable for expression, or the default one if the portrait
+// doesn't have it.
+func (p Portrait) Face(expression string) common.Drawable {
+	if d, ok := p[expression]; ok {
+		return d
+	}
+	return p[""]
+}
+
+// Blip is the sound a speaker makes as their lines are typed.
+type Blip struct {
+	// Pitch is the blip's frequency in hertz. If it's 0 the speaker's Clip
+	// plays instead.
+	Pitch float32
+	// Every is how many letters are typed between blips. If it's 0, the
+	// next blip plays as soon as the last one's finished.
+	Every int
+}
+
+// blipRate is the sample rate of the blips, and blipLength how long each
+// one is in seconds.
+const (
+	blipRate   = 22050
+	blipLength = 0.04
+)
+
+// blipURL is where the blip at pitch is loaded, making it if it hasn't been
+// yet.
+func blipURL(pitch float32) (string, error) {
+	url := fmt.Sprintf("blip/%.0f.wav", pitch)
+	if _, err := engo.Files.Resource(url); err == nil {
+		return url, nil
+	}
+	if err := engo.Files.LoadReaderData(url, bytes.NewReader(blipWAV(pitch))); err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// blipWAV is a short square wave at pitch that fades out, as a 16 bit mono
+// WAV file.
+func blipWAV(pitch float32) []byte {
+	n := int(blipRate * blipLength)
+	samples := make([]int16, n)
+	for i := range samples {
+		t := float64(i) / blipRate
+		v := 0.3 * (1 - float64(i)/float64(n))
+		if math.Mod(t*float64(pitch), 1) >= 0.5 {
+			v = -v
+		}
+		samples[i] = int16(v * math.MaxInt16)
+	}
+
+	buf := &bytes.Buffer{}
+	write := func(v interface{}) { binary.Write(buf, binary.LittleEndian, v) }
+	buf.WriteString("RIFF")
+	write(uint32(36 + 2*n))
+	buf.WriteString("WAVEfmt ")
+	write(uint32(16))
+	write(uint16(1)) // PCM
+	write(uint16(1)) // mono
+	write(uint32(blipRate))
+	write(uint32(2 * blipRate))
+	write(uint16(2))
+	write(uint16(16))
+	buf.WriteString("data")
+	write(uint32(2 * n))
+	write(samples)
+	return buf.Bytes()
+}
+
+// logMessage is msg said by speaker in this voice, making face.
+func (c ChatComponent) logMessage(speaker, face, msg string) CombatLogMessage {
+	m := CombatLogMessage{
+		Msg:      msg,
+		Fnt:      c.Font,
+		Clip:     c.Clip,
+		Speaker:  speaker,
+		Portrait: c.Portrait.Face(face),
+		Blip:     c.Blip,
+	}
+	if c.Dot != nil {
+		m.Dot = *c.Dot
+	}
+	return m
+}
+
+// logMessage is msg said in the voice, with its face.
+func (v Voice) logMessage(msg string) CombatLogMessage {
+	return CombatLogMessage{
+		Msg:      msg,
+		Fnt:      v.Font,
+		Clip:     v.Clip,
+		Speaker:  v.Name,
+		Portrait: v.Portrait.Face(v.Face),
+		Blip:     v.Blip,
+	}
+}